@@ -0,0 +1,122 @@
+package currency
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ParseAmount returns the decimal value represented by the string. It accepts
+// the common ways of writing amounts:
+//
+//	amount  = [sign] digits [decimal digits]
+//	        | [sign] group {sep group3} [decimal digits]
+//	sign    = "+" | "-"
+//	decimal = "." | ","
+//	sep     = "." | "," | " " | "'"
+//
+// where group is one to three digits and group3 exactly three digits. Either
+// the integer or the fractional digits may be omitted, but not both. The
+// separators between the groups must all be the same character. A lone "." or
+// "," is the decimal separator, so "12,50" is 12.5, except that it is
+// ambiguous when it follows one to three digits, not starting with 0, and is
+// followed by exactly three digits: "1,250" and "1.250" are rejected rather
+// than guessed, while "0.125", "1250.125" and "1.2500" are accepted. When both
+// "." and "," occur the last one is the decimal separator, so "1,250.50" and
+// "1.250,50" are both 1250.5. Leading and trailing white space is ignored and
+// non-breaking spaces count as " ". Exponents are not accepted.
+//
+// An ErrInvalidAmount holding s is returned if s is not a valid amount.
+func ParseAmount(s string) (decimal.Decimal, error) {
+	v := strings.TrimSpace(strings.NewReplacer("\u00a0", " ", "\u202f", " ").Replace(s))
+	sign := ""
+
+	if v != "" && (v[0] == '-' || v[0] == '+') {
+		if v[0] == '-' {
+			sign = "-"
+		}
+
+		v = v[1:]
+	}
+
+	// find the decimal separator.
+	var sep byte
+	dot, comma := strings.LastIndexByte(v, '.'), strings.LastIndexByte(v, ',')
+
+	switch {
+	case dot >= 0 && comma >= 0 && dot > comma:
+		sep = '.'
+	case dot >= 0 && comma >= 0:
+		sep = ','
+	case dot >= 0 && strings.Count(v, ".") == 1:
+		sep = '.'
+	case comma >= 0 && strings.Count(v, ",") == 1:
+		sep = ','
+	}
+
+	intPart, frac := v, ""
+
+	if sep != 0 {
+		i := strings.LastIndexByte(v, sep)
+		intPart, frac = v[:i], v[i+1:]
+	}
+
+	// a lone separator between a single group and three digits may be a
+	// decimal or a group separator.
+	if sep != 0 && len(frac) == 3 && len(intPart) >= 1 && len(intPart) <= 3 &&
+		intPart[0] != '0' && isDigits(intPart) && isDigits(frac) {
+		return decimal.Zero, ErrInvalidAmount{Value: s}
+	}
+
+	digits, ok := parseGroups(intPart)
+
+	if !ok || !isDigits(frac) || digits == "" && frac == "" {
+		return decimal.Zero, ErrInvalidAmount{Value: s}
+	}
+
+	if digits == "" {
+		digits = "0"
+	}
+
+	if frac != "" {
+		digits += "." + frac
+	}
+
+	d, err := decimal.NewFromString(sign + digits)
+
+	if err != nil {
+		return decimal.Zero, ErrInvalidAmount{Value: s}
+	}
+
+	return d, nil
+}
+
+// parseGroups returns the digits of the integer part v with the group
+// separators removed.
+func parseGroups(v string) (string, bool) {
+	i := strings.IndexAny(v, ".,' ")
+
+	if i < 0 {
+		return v, isDigits(v)
+	}
+
+	groups := strings.Split(v, v[i:i+1])
+
+	for i, g := range groups {
+		if !isDigits(g) || g == "" || len(g) > 3 || i > 0 && len(g) != 3 {
+			return "", false
+		}
+	}
+
+	return strings.Join(groups, ""), true
+}
+
+func isDigits(v string) bool {
+	for i := 0; i < len(v); i++ {
+		if v[i] < '0' || v[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
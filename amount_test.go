@@ -0,0 +1,97 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package currency
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value string
+		exp   string
+	}{
+		{"12.50", "12.5"},
+		{"12,50", "12.5"},
+		{" -12.5 ", "-12.5"},
+		{"+3", "3"},
+		{".5", "0.5"},
+		{"5.", "5"},
+		{"1,2500", "1.25"},
+		{"0.125", "0.125"},
+		{"0,125", "0.125"},
+		{"1250.125", "1250.125"},
+		{"1234,567", "1234.567"},
+		{"1 234,567", "1234.567"},
+		{"1,250", ""},
+		{"1.250", ""},
+		{"-999,999", ""},
+		{"1,250.50", "1250.5"},
+		{"1.250,50", "1250.5"},
+		{"1,234,567", "1234567"},
+		{"1.234.567,89", "1234567.89"},
+		{"1 234 567,89", "1234567.89"},
+		{"1 234,5", "1234.5"},
+		{"1'234.50", "1234.5"},
+		{"0", "0"},
+		{"", ""},
+		{"abc", ""},
+		{"-", ""},
+		{".", ""},
+		{"1e3", ""},
+		{"1.2.3", ""},
+		{"1,2.5", ""},
+		{"1,234 567", ""},
+		{"12,345,67", ""},
+		{"1 2", ""},
+		{"--1", ""},
+	}
+
+	for i, test := range tests {
+		res, err := ParseAmount(test.value)
+
+		if test.exp == "" {
+			if !errors.As(err, new(ErrInvalidAmount)) || err.(ErrInvalidAmount).Value != test.value {
+				t.Fatalf("test %d: expect ErrInvalidAmount for %q, got %v", i, test.value, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+
+		if res.String() != test.exp {
+			t.Fatalf("test %d: expect %s, got %s", i, test.exp, res)
+		}
+	}
+}
+
+func TestConvertStringInvalidAmount(t *testing.T) {
+	p := newStubProvider()
+	cc := NewWithProvider(p)
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+
+	if _, err := cc.ConvertStringAt("abc", USD, EUR, at); !errors.As(err, new(ErrInvalidAmount)) {
+		t.Fatalf("expect ErrInvalidAmount, got %v", err)
+	}
+
+	if p.calls != 0 {
+		t.Fatalf("expect no provider calls, got %d", p.calls)
+	}
+
+	res, err := cc.ConvertStringAt("1.000,00", EUR, USD, at)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.StringFixed(4) != "1125.6000" {
+		t.Fatalf("expect 1125.6000, got %s", res.StringFixed(4))
+	}
+}
@@ -0,0 +1,110 @@
+package currency
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// rateCache holds the exchange rates per date. It evicts the least recently
+// used date once it holds more than size dates and treats entries past their
+// expiry time as missing. Pinned entries never expire, are never evicted and
+// do not count towards size. It's not safe for concurrent use; get may be called
+// concurrently under a read lock, put requires an exclusive lock.
+type rateCache struct {
+	tick    uint64 // accessed atomically
+	size    int
+	pinned  int
+	entries map[date]*cacheEntry
+}
+
+type cacheEntry struct {
+	used    uint64 // accessed atomically
+	day     map[Currency]ExchangeRate
+	expires time.Time
+	pinned  bool
+}
+
+func newRateCache(size int) *rateCache {
+	return &rateCache{
+		size:    size,
+		entries: make(map[date]*cacheEntry),
+	}
+}
+
+// get returns the exchange rates cached for key unless they expired before
+// now.
+func (c *rateCache) get(key date, now time.Time) (map[Currency]ExchangeRate, bool) {
+	e, ok := c.entries[key]
+
+	if !ok || e.expired(now) {
+		return nil, false
+	}
+
+	atomic.StoreUint64(&e.used, atomic.AddUint64(&c.tick, 1))
+	return e.day, true
+}
+
+// put adds the exchange rates for key. A zero expires means the entry never
+// expires.
+func (c *rateCache) put(key date, day map[Currency]ExchangeRate, expires, now time.Time) {
+	c.add(key, &cacheEntry{day: day, expires: expires})
+
+	if c.size <= 0 {
+		return
+	}
+
+	for len(c.entries)-c.pinned > c.size {
+		c.evict(now)
+	}
+}
+
+// pin adds the exchange rates for key as a pinned entry.
+func (c *rateCache) pin(key date, day map[Currency]ExchangeRate) {
+	c.add(key, &cacheEntry{day: day, pinned: true})
+}
+
+func (c *rateCache) add(key date, e *cacheEntry) {
+	if old, ok := c.entries[key]; ok && old.pinned {
+		c.pinned--
+	}
+
+	if e.pinned {
+		c.pinned++
+	}
+
+	e.used = atomic.AddUint64(&c.tick, 1)
+	c.entries[key] = e
+}
+
+// evict removes an expired entry or, if there is none, the least recently used
+// entry which is not pinned.
+func (c *rateCache) evict(now time.Time) {
+	var (
+		oldest date
+		used   uint64
+		found  bool
+	)
+
+	for key, e := range c.entries {
+		if e.pinned {
+			continue
+		}
+
+		if e.expired(now) {
+			delete(c.entries, key)
+			return
+		}
+
+		if u := atomic.LoadUint64(&e.used); !found || u < used {
+			oldest, used, found = key, u, true
+		}
+	}
+
+	if found {
+		delete(c.entries, oldest)
+	}
+}
+
+func (e *cacheEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
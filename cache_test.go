@@ -0,0 +1,116 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package currency
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func newDatesStubProvider(days ...time.Time) *stubProvider {
+	p := &stubProvider{rates: make(map[date]Rates)}
+
+	for _, d := range days {
+		p.rates[toDate(d)] = Rates{USD: decimal.RequireFromString("1.1256")}
+	}
+
+	return p
+}
+
+func TestExchangeCacheLRU(t *testing.T) {
+	d1 := time.Date(2016, 9, 5, 0, 0, 0, 0, time.UTC)
+	d2 := d1.AddDate(0, 0, 1)
+	d3 := d1.AddDate(0, 0, 2)
+	p := newDatesStubProvider(d1, d2, d3)
+	ex := NewExchange(p, WithCacheSize(2))
+
+	// d1 is used after d2 so d2 is evicted when d3 is added.
+	for i, at := range []time.Time{d1, d2, d1, d3, d1, d3, d2} {
+		if _, err := ex.Get(at, USD); err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+	}
+
+	if p.calls != 4 {
+		t.Fatalf("expect 4 provider calls, got %d", p.calls)
+	}
+
+	if len(ex.cache.entries) != 2 {
+		t.Fatalf("expect 2 cached dates, got %d", len(ex.cache.entries))
+	}
+}
+
+func TestExchangeCacheTTL(t *testing.T) {
+	now := time.Date(2016, 9, 6, 12, 0, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+	clock := &fakeClock{now: now}
+	p := newDatesStubProvider(now, yesterday)
+	ex := NewExchange(p, WithClock(clock), WithCacheTTL(24*time.Hour), WithTodayTTL(time.Hour))
+
+	get := func(at time.Time) {
+		if _, err := ex.Get(at, USD); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	get(now)
+	get(yesterday)
+	clock.Advance(30 * time.Minute)
+	get(now)
+	get(yesterday)
+
+	if p.calls != 2 {
+		t.Fatalf("expect 2 provider calls, got %d", p.calls)
+	}
+
+	clock.Advance(time.Hour)
+	get(now)
+	get(yesterday)
+
+	if p.calls != 3 {
+		t.Fatalf("expect today to expire, got %d provider calls", p.calls)
+	}
+
+	clock.Advance(24 * time.Hour)
+	get(yesterday)
+
+	if p.calls != 4 {
+		t.Fatalf("expect yesterday to expire, got %d provider calls", p.calls)
+	}
+}
+
+func TestExchangeCacheLoaded(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2016, 9, 7, 12, 0, 0, 0, time.UTC)}
+	p := newDatesStubProvider(time.Date(2016, 9, 1, 0, 0, 0, 0, time.UTC))
+	ex := NewExchange(p, WithClock(clock), WithCacheSize(1), WithCacheTTL(time.Hour))
+
+	if err := ex.Load(strings.NewReader(testECBCSV)); err != nil {
+		t.Fatal(err)
+	}
+
+	// fetching another date does not evict the loaded dates.
+	if _, err := ex.Get(time.Date(2016, 9, 1, 0, 0, 0, 0, time.UTC), USD); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(2 * time.Hour)
+
+	for i, day := range []int{2, 5, 6} {
+		if _, err := ex.Get(time.Date(2016, 9, day, 0, 0, 0, 0, time.UTC), USD); err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+	}
+
+	if p.calls != 1 {
+		t.Fatalf("expect 1 provider call, got %d", p.calls)
+	}
+
+	if len(ex.cache.entries) != 4 {
+		t.Fatalf("expect 4 cached dates, got %d", len(ex.cache.entries))
+	}
+}
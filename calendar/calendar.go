@@ -0,0 +1,127 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package calendar implements the business day calendar of the TARGET2
+// payment system, which the European Central Bank follows when publishing the
+// euro foreign exchange reference rates.
+//
+// TARGET2 is closed on Saturdays, Sundays, New Year's Day, Good Friday,
+// Easter Monday, 1 May, Christmas Day and 26 December. The functions use the
+// calendar date of the given time in its own location.
+package calendar
+
+import "time"
+
+// IsBusinessDay reports whether TARGET2 is open on the date of t.
+func IsBusinessDay(t time.Time) bool {
+	switch t.Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+
+	return !IsHoliday(t)
+}
+
+// IsHoliday reports whether the date of t is a TARGET2 holiday. Weekends are
+// not holidays but are not business days either.
+func IsHoliday(t time.Time) bool {
+	year, month, day := t.Date()
+
+	switch {
+	case month == time.January && day == 1,
+		month == time.May && day == 1,
+		month == time.December && (day == 25 || day == 26):
+		return true
+	}
+
+	easter := Easter(year)
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return date.Equal(easter.AddDate(0, 0, -2)) || date.Equal(easter.AddDate(0, 0, 1))
+}
+
+// Easter returns the date of Easter Sunday in the Gregorian calendar for the
+// given year, at midnight UTC.
+func Easter(year int) time.Time {
+	// anonymous Gregorian algorithm (Meeus/Jones/Butcher).
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// PreviousBusinessDay returns the last business day before the date of t. The
+// time of day and location of t are kept.
+func PreviousBusinessDay(t time.Time) time.Time {
+	for {
+		t = t.AddDate(0, 0, -1)
+
+		if IsBusinessDay(t) {
+			return t
+		}
+	}
+}
+
+// NextBusinessDay returns the first business day after the date of t. The time
+// of day and location of t are kept.
+func NextBusinessDay(t time.Time) time.Time {
+	for {
+		t = t.AddDate(0, 0, 1)
+
+		if IsBusinessDay(t) {
+			return t
+		}
+	}
+}
+
+// AddBusinessDays returns the date n business days after t, or before t if n
+// is negative. If t is not a business day, adding one business day gives the
+// next business day.
+func AddBusinessDays(t time.Time, n int) time.Time {
+	for ; n > 0; n-- {
+		t = NextBusinessDay(t)
+	}
+
+	for ; n < 0; n++ {
+		t = PreviousBusinessDay(t)
+	}
+
+	return t
+}
+
+// BusinessDaysBetween returns the number of business days from the date of
+// start, inclusive, to the date of end, exclusive. It's negative if end is
+// before start.
+func BusinessDaysBetween(start, end time.Time) int {
+	sign := 1
+
+	if end.Before(start) {
+		start, end = end, start
+		sign = -1
+	}
+
+	y, m, d := start.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	y, m, d = end.Date()
+	last := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	n := 0
+
+	for ; day.Before(last); day = day.AddDate(0, 0, 1) {
+		if IsBusinessDay(day) {
+			n++
+		}
+	}
+
+	return sign * n
+}
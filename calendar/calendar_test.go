@@ -0,0 +1,89 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestEaster(t *testing.T) {
+	tests := []time.Time{
+		date(2000, 4, 23),
+		date(2008, 3, 23),
+		date(2011, 4, 24),
+		date(2016, 3, 27),
+		date(2019, 4, 21),
+		date(2024, 3, 31),
+		date(2038, 4, 25),
+	}
+
+	for _, exp := range tests {
+		if got := Easter(exp.Year()); !got.Equal(exp) {
+			t.Fatalf("%d: expect %s, got %s", exp.Year(), exp.Format("2006-01-02"), got.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestIsBusinessDay(t *testing.T) {
+	tests := []struct {
+		at  time.Time
+		exp bool
+	}{
+		{date(2016, 1, 1), false},
+		{date(2016, 3, 24), true},
+		{date(2016, 3, 25), false},
+		{date(2016, 3, 28), false},
+		{date(2016, 3, 29), true},
+		{date(2019, 5, 1), false},
+		{date(2016, 9, 3), false},
+		{date(2016, 9, 4), false},
+		{date(2016, 9, 6), true},
+		{date(2016, 12, 26), false},
+		{date(2017, 12, 25), false},
+		{date(2017, 12, 27), true},
+		{time.Date(2016, 3, 25, 23, 30, 0, 0, time.FixedZone("CET", 3600)), false},
+	}
+
+	for _, test := range tests {
+		if got := IsBusinessDay(test.at); got != test.exp {
+			t.Fatalf("%s: expect %v, got %v", test.at, test.exp, got)
+		}
+	}
+}
+
+func TestBusinessDayArithmetic(t *testing.T) {
+	if got := PreviousBusinessDay(date(2016, 3, 29)); !got.Equal(date(2016, 3, 24)) {
+		t.Fatalf("expect 2016-03-24, got %s", got)
+	}
+
+	if got := NextBusinessDay(date(2016, 3, 24)); !got.Equal(date(2016, 3, 29)) {
+		t.Fatalf("expect 2016-03-29, got %s", got)
+	}
+
+	if got := AddBusinessDays(date(2016, 12, 23), 2); !got.Equal(date(2016, 12, 28)) {
+		t.Fatalf("expect 2016-12-28, got %s", got)
+	}
+
+	if got := AddBusinessDays(date(2016, 12, 28), -2); !got.Equal(date(2016, 12, 23)) {
+		t.Fatalf("expect 2016-12-23, got %s", got)
+	}
+
+	if got := AddBusinessDays(date(2016, 9, 6), 0); !got.Equal(date(2016, 9, 6)) {
+		t.Fatalf("expect 2016-09-06, got %s", got)
+	}
+
+	if n := BusinessDaysBetween(date(2016, 3, 21), date(2016, 4, 4)); n != 8 {
+		t.Fatalf("expect 8 business days, got %d", n)
+	}
+
+	if n := BusinessDaysBetween(date(2016, 4, 4), date(2016, 3, 21)); n != -8 {
+		t.Fatalf("expect -8 business days, got %d", n)
+	}
+}
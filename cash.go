@@ -0,0 +1,60 @@
+package currency
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// cashIncrements holds the smallest amount payable in cash of the currencies
+// whose cash payments are rounded to more than their minor unit, because the
+// smallest coins have been withdrawn. Some euro countries, e.g. Finland and
+// the Netherlands, round cash payments to 0.05 too, but as this is not
+// common to the currency EUR is not listed.
+var cashIncrements = map[Currency]decimal.Decimal{
+	AUD: decimal.New(5, -2),
+	CAD: decimal.New(5, -2),
+	CHF: decimal.New(5, -2),
+	CZK: decimal.New(1, 0),
+	DKK: decimal.New(50, -2),
+	HKD: decimal.New(10, -2),
+	HUF: decimal.New(5, 0),
+	ILS: decimal.New(10, -2),
+	MYR: decimal.New(5, -2),
+	NOK: decimal.New(1, 0),
+	NZD: decimal.New(10, -2),
+	SEK: decimal.New(1, 0),
+	SGD: decimal.New(5, -2),
+	ZAR: decimal.New(10, -2),
+}
+
+// CashIncrement returns the smallest amount of the currency payable in cash,
+// e.g. 0.05 for CHF and 1 for SEK. For currencies without a cash rounding rule
+// it is the minor unit, and zero if the currency has no minor unit.
+func (c Currency) CashIncrement() decimal.Decimal {
+	if inc, ok := cashIncrements[c]; ok {
+		return inc
+	}
+
+	places := c.MinorUnits()
+
+	if places < 0 {
+		return decimal.Zero
+	}
+
+	return decimal.New(1, -int32(places))
+}
+
+// RoundCash returns m rounded to a multiple of the cash increment of its
+// currency using the given rounding mode, which gives the amount payable in
+// cash. Point of sale totals are usually rounded with RoundHalfUp, e.g.
+// 12.32 CHF to 12.30 and 12.33 CHF to 12.35. m is returned unchanged if its
+// currency has no minor unit.
+func (m Money) RoundCash(mode RoundingMode) Money {
+	inc := m.Currency.CashIncrement()
+
+	if inc.IsZero() {
+		return m
+	}
+
+	n := mode.Div(m.Amount, inc, 0)
+	return NewMoney(n.Mul(inc), m.Currency)
+}
@@ -0,0 +1,54 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package currency
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMoneyRoundCash(t *testing.T) {
+	tests := []struct {
+		value string
+		cur   Currency
+		mode  RoundingMode
+		exp   string
+	}{
+		{"12.32", CHF, RoundHalfUp, "12.3"},
+		{"12.325", CHF, RoundHalfUp, "12.35"},
+		{"12.33", CHF, RoundHalfUp, "12.35"},
+		{"12.37", CHF, RoundDown, "12.35"},
+		{"-12.33", CHF, RoundHalfUp, "-12.35"},
+		{"99.50", SEK, RoundHalfUp, "100"},
+		{"99.49", SEK, RoundHalfUp, "99"},
+		{"99.50", NOK, RoundHalfEven, "100"},
+		{"98.50", NOK, RoundHalfEven, "98"},
+		{"10.24", DKK, RoundHalfUp, "10"},
+		{"10.25", DKK, RoundHalfUp, "10.5"},
+		{"1.02", CAD, RoundHalfUp, "1"},
+		{"1.03", CAD, RoundHalfUp, "1.05"},
+		{"1.01", CAD, RoundUp, "1.05"},
+		{"1.024", USD, RoundHalfUp, "1.02"},
+		{"1234.5", JPY, RoundHalfUp, "1235"},
+		{"1.23456", XAU, RoundHalfUp, "1.23456"},
+	}
+
+	for i, test := range tests {
+		m := NewMoney(decimal.RequireFromString(test.value), test.cur).RoundCash(test.mode)
+
+		if m.Currency != test.cur || m.Amount.String() != test.exp {
+			t.Fatalf("test %d: expect %s %s, got %s", i, test.exp, test.cur, m)
+		}
+	}
+
+	if inc := XAU.CashIncrement(); !inc.IsZero() {
+		t.Fatalf("expect 0, got %s", inc)
+	}
+
+	if inc := BHD.CashIncrement(); inc.String() != "0.001" {
+		t.Fatalf("expect 0.001, got %s", inc)
+	}
+}
@@ -0,0 +1,20 @@
+package currency
+
+import "time"
+
+// Clock provides the current time and timers to an Exchange. It allows tests
+// to control time.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
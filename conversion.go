@@ -0,0 +1,35 @@
+package currency
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Conversion is the result of converting an amount between two currencies
+// along with the exchange rate used and its provenance, suitable for keeping
+// an audit trail.
+type Conversion struct {
+	Amount decimal.Decimal `json:"amount"`
+	From   Currency        `json:"from"`
+	Result decimal.Decimal `json:"result"`
+	To     Currency        `json:"to"`
+
+	// Rate is the effective exchange rate: Result is Amount times Rate,
+	// rounded to the minor units of To if WithRounding is given.
+	Rate decimal.Decimal `json:"rate"`
+
+	// Path lists the currencies the rate was derived through, e.g. USD,
+	// EUR, PLN for a cross rate via EUR.
+	Path []Currency `json:"path"`
+
+	// At is the requested instant.
+	At time.Time `json:"at"`
+
+	// Date is the date of the exchange rates used, Source where they were
+	// obtained from and Fetched when. See ExchangeRate. They are zero for a
+	// conversion from EUR to EUR, which needs no exchange rate.
+	Date    time.Time `json:"date"`
+	Source  string    `json:"source"`
+	Fetched time.Time `json:"fetched"`
+}
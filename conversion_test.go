@@ -0,0 +1,90 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package currency
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestConvertDetailed(t *testing.T) {
+	now := time.Date(2016, 9, 7, 12, 0, 0, 0, time.UTC)
+	cc := NewWithProvider(newStubProvider(), WithClock(&fakeClock{now: now}))
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+	date := time.Date(2016, 9, 6, 0, 0, 0, 0, time.UTC)
+	value := decimal.New(100, 0)
+
+	tests := []struct {
+		from, to Currency
+		path     []Currency
+		source   string
+	}{
+		{USD, PLN, []Currency{USD, EUR, PLN}, "*currency.stubProvider"},
+		{USD, EUR, []Currency{USD, EUR}, "*currency.stubProvider"},
+		{EUR, PLN, []Currency{EUR, PLN}, "*currency.stubProvider"},
+		{PLN, PLN, []Currency{PLN, PLN}, "*currency.stubProvider"},
+		{EUR, EUR, []Currency{EUR, EUR}, ""},
+	}
+
+	for i, tt := range tests {
+		conv, err := cc.ConvertDetailedAt(value, tt.from, tt.to, at)
+
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+
+		expect, err := cc.ConvertAt(value, tt.from, tt.to, at)
+
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+
+		if !conv.Result.Equal(expect) || !conv.Amount.Mul(conv.Rate).Equal(conv.Result) {
+			t.Fatalf("test %d: expect %s, got %s at rate %s", i, expect, conv.Result, conv.Rate)
+		}
+
+		if !reflect.DeepEqual(conv.Path, tt.path) {
+			t.Fatalf("test %d: expect path %v, got %v", i, tt.path, conv.Path)
+		}
+
+		if conv.Source != tt.source {
+			t.Fatalf("test %d: expect source %q, got %q", i, tt.source, conv.Source)
+		}
+
+		if tt.source != "" && (!conv.Date.Equal(date) || !conv.Fetched.Equal(now)) {
+			t.Fatalf("test %d: expect date %s fetched %s, got %s fetched %s", i, date, now, conv.Date, conv.Fetched)
+		}
+
+		if !conv.At.Equal(at) {
+			t.Fatalf("test %d: expect requested %s, got %s", i, at, conv.At)
+		}
+	}
+
+	conv, err := cc.ConvertDetailedAt(value, USD, PLN, at)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := json.Marshal(conv)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got Conversion
+
+	if err := json.Unmarshal(buf, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if !got.Result.Equal(conv.Result) || got.Source != conv.Source || !reflect.DeepEqual(got.Path, conv.Path) {
+		t.Fatalf("expect %+v, got %+v", conv, got)
+	}
+}
@@ -6,6 +6,7 @@
 package currency
 
 import (
+	"context"
 	"database/sql/driver"
 	"errors"
 	"fmt"
@@ -54,187 +55,187 @@ func (c Currency) Value() (driver.Value, error) {
 
 const (
 	AED Currency = "AED" // United Arab Emirates Dirham
-	AFN          = "AFN" // Afghanistan Afghani
-	ALL          = "ALL" // Albania Lek
-	AMD          = "AMD" // Armenia Dram
-	ANG          = "ANG" // Netherlands Antilles Guilder
-	AOA          = "AOA" // Angola Kwanza
-	ARS          = "ARS" // Argentina Peso
-	AUD          = "AUD" // Australia Dollar
-	AWG          = "AWG" // Aruba Guilder
-	AZN          = "AZN" // Azerbaijan New Manat
-	BAM          = "BAM" // Bosnia and Herzegovina Convertible Marka
-	BBD          = "BBD" // Barbados Dollar
-	BDT          = "BDT" // Bangladesh Taka
-	BGN          = "BGN" // Bulgaria Lev
-	BHD          = "BHD" // Bahrain Dinar
-	BIF          = "BIF" // Burundi Franc
-	BMD          = "BMD" // Bermuda Dollar
-	BND          = "BND" // Brunei Darussalam Dollar
-	BOB          = "BOB" // Bolivia Boliviano
-	BRL          = "BRL" // Brazil Real
-	BSD          = "BSD" // Bahamas Dollar
-	BTN          = "BTN" // Bhutan Ngultrum
-	BWP          = "BWP" // Botswana Pula
-	BYR          = "BYR" // Belarus Ruble
-	BZD          = "BZD" // Belize Dollar
-	CAD          = "CAD" // Canada Dollar
-	CDF          = "CDF" // Congo/Kinshasa Franc
-	CHF          = "CHF" // Switzerland Franc
-	CLP          = "CLP" // Chile Peso
-	CNY          = "CNY" // China Yuan Renminbi
-	COP          = "COP" // Colombia Peso
-	CRC          = "CRC" // Costa Rica Colon
-	CUC          = "CUC" // Cuba Convertible Peso
-	CUP          = "CUP" // Cuba Peso
-	CVE          = "CVE" // Cape Verde Escudo
-	CZK          = "CZK" // Czech Republic Koruna
-	DJF          = "DJF" // Djibouti Franc
-	DKK          = "DKK" // Denmark Krone
-	DOP          = "DOP" // Dominican Republic Peso
-	DZD          = "DZD" // Algeria Dinar
-	EGP          = "EGP" // Egypt Pound
-	ERN          = "ERN" // Eritrea Nakfa
-	ETB          = "ETB" // Ethiopia Birr
-	EUR          = "EUR" // Euro Member Countries
-	FJD          = "FJD" // Fiji Dollar
-	FKP          = "FKP" // Falkland Islands (Malvinas) Pound
-	GBP          = "GBP" // United Kingdom Pound
-	GEL          = "GEL" // Georgia Lari
-	GGP          = "GGP" // Guernsey Pound
-	GHS          = "GHS" // Ghana Cedi
-	GIP          = "GIP" // Gibraltar Pound
-	GMD          = "GMD" // Gambia Dalasi
-	GNF          = "GNF" // Guinea Franc
-	GTQ          = "GTQ" // Guatemala Quetzal
-	GYD          = "GYD" // Guyana Dollar
-	HKD          = "HKD" // Hong Kong Dollar
-	HNL          = "HNL" // Honduras Lempira
-	HRK          = "HRK" // Croatia Kuna
-	HTG          = "HTG" // Haiti Gourde
-	HUF          = "HUF" // Hungary Forint
-	IDR          = "IDR" // Indonesia Rupiah
-	ILS          = "ILS" // Israel Shekel
-	IMP          = "IMP" // Isle of Man Pound
-	INR          = "INR" // India Rupee
-	IQD          = "IQD" // Iraq Dinar
-	IRR          = "IRR" // Iran Rial
-	ISK          = "ISK" // Iceland Krona
-	JEP          = "JEP" // Jersey Pound
-	JMD          = "JMD" // Jamaica Dollar
-	JOD          = "JOD" // Jordan Dinar
-	JPY          = "JPY" // Japan Yen
-	KES          = "KES" // Kenya Shilling
-	KGS          = "KGS" // Kyrgyzstan Som
-	KHR          = "KHR" // Cambodia Riel
-	KMF          = "KMF" // Comoros Franc
-	KPW          = "KPW" // Korea (North) Won
-	KRW          = "KRW" // Korea (South) Won
-	KWD          = "KWD" // Kuwait Dinar
-	KYD          = "KYD" // Cayman Islands Dollar
-	KZT          = "KZT" // Kazakhstan Tenge
-	LAK          = "LAK" // Laos Kip
-	LBP          = "LBP" // Lebanon Pound
-	LKR          = "LKR" // Sri Lanka Rupee
-	LRD          = "LRD" // Liberia Dollar
-	LSL          = "LSL" // Lesotho Loti
-	LYD          = "LYD" // Libya Dinar
-	MAD          = "MAD" // Morocco Dirham
-	MDL          = "MDL" // Moldova Leu
-	MGA          = "MGA" // Madagascar Ariary
-	MKD          = "MKD" // Macedonia Denar
-	MMK          = "MMK" // Myanmar (Burma) Kyat
-	MNT          = "MNT" // Mongolia Tughrik
-	MOP          = "MOP" // Macau Pataca
-	MRO          = "MRO" // Mauritania Ouguiya
-	MUR          = "MUR" // Mauritius Rupee
-	MVR          = "MVR" // Maldives (Maldive Islands) Rufiyaa
-	MWK          = "MWK" // Malawi Kwacha
-	MXN          = "MXN" // Mexico Peso
-	MXV          = "MXV" // Mexican Unidad de Inversion (UDI) (funds code)
-	MYR          = "MYR" // Malaysia Ringgit
-	MZN          = "MZN" // Mozambique Metical
-	NAD          = "NAD" // Namibia Dollar
-	NGN          = "NGN" // Nigeria Naira
-	NIO          = "NIO" // Nicaragua Cordoba
-	NOK          = "NOK" // Norway Krone
-	NPR          = "NPR" // Nepal Rupee
-	NZD          = "NZD" // New Zealand Dollar
-	OMR          = "OMR" // Oman Rial
-	PAB          = "PAB" // Panama Balboa
-	PEN          = "PEN" // Peru Nuevo Sol
-	PGK          = "PGK" // Papua New Guinea Kina
-	PHP          = "PHP" // Philippines Peso
-	PKR          = "PKR" // Pakistan Rupee
-	PLN          = "PLN" // Poland Zloty
-	PYG          = "PYG" // Paraguay Guarani
-	QAR          = "QAR" // Qatar Riyal
-	RON          = "RON" // Romania New Leu
-	RSD          = "RSD" // Serbia Dinar
-	RUB          = "RUB" // Russia Ruble
-	RWF          = "RWF" // Rwanda Franc
-	SAR          = "SAR" // Saudi Arabia Riyal
-	SBD          = "SBD" // Solomon Islands Dollar
-	SCR          = "SCR" // Seychelles Rupee
-	SDG          = "SDG" // Sudan Pound
-	SEK          = "SEK" // Sweden Krona
-	SGD          = "SGD" // Singapore Dollar
-	SHP          = "SHP" // Saint Helena Pound
-	SLL          = "SLL" // Sierra Leone Leone
-	SOS          = "SOS" // Somalia Shilling
-	SPL          = "SPL" // Seborga Luigino
-	SRD          = "SRD" // Suriname Dollar
-	STD          = "STD" // São Tomé and Príncipe Dobra
-	SVC          = "SVC" // El Salvador Colon
-	SYP          = "SYP" // Syria Pound
-	SZL          = "SZL" // Swaziland Lilangeni
-	THB          = "THB" // Thailand Baht
-	TJS          = "TJS" // Tajikistan Somoni
-	TMT          = "TMT" // Turkmenistan Manat
-	TND          = "TND" // Tunisia Dinar
-	TOP          = "TOP" // Tonga Pa'anga
-	TRY          = "TRY" // Turkey Lira
-	TTD          = "TTD" // Trinidad and Tobago Dollar
-	TVD          = "TVD" // Tuvalu Dollar
-	TWD          = "TWD" // Taiwan New Dollar
-	TZS          = "TZS" // Tanzania Shilling
-	UAH          = "UAH" // Ukraine Hryvnia
-	UGX          = "UGX" // Uganda Shilling
-	USD          = "USD" // United States Dollar
-	UYU          = "UYU" // Uruguay Peso
-	UZS          = "UZS" // Uzbekistan Som
-	VEF          = "VEF" // Venezuela Bolivar
-	VND          = "VND" // Viet Nam Dong
-	VUV          = "VUV" // Vanuatu Vatu
-	WST          = "WST" // Samoa Tala
-	XAF          = "XAF" // Communauté Financière Africaine (BEAC) CFA Franc BEAC
-	XCD          = "XCD" // East Caribbean Dollar
-	XDR          = "XDR" // International Monetary Fund (IMF) Special Drawing Rights
-	XOF          = "XOF" // Communauté Financière Africaine (BCEAO) Franc
-	XPF          = "XPF" // Comptoirs Français du Pacifique (CFP) Franc
-	YER          = "YER" // Yemen Rial
-	ZAR          = "ZAR" // South Africa Rand
-	ZMW          = "ZMW" // Zambia Kwacha
-	ZWD          = "ZWD" // Zimbabwe Dollar
+	AFN Currency = "AFN" // Afghanistan Afghani
+	ALL Currency = "ALL" // Albania Lek
+	AMD Currency = "AMD" // Armenia Dram
+	ANG Currency = "ANG" // Netherlands Antilles Guilder
+	AOA Currency = "AOA" // Angola Kwanza
+	ARS Currency = "ARS" // Argentina Peso
+	AUD Currency = "AUD" // Australia Dollar
+	AWG Currency = "AWG" // Aruba Guilder
+	AZN Currency = "AZN" // Azerbaijan New Manat
+	BAM Currency = "BAM" // Bosnia and Herzegovina Convertible Marka
+	BBD Currency = "BBD" // Barbados Dollar
+	BDT Currency = "BDT" // Bangladesh Taka
+	BGN Currency = "BGN" // Bulgaria Lev
+	BHD Currency = "BHD" // Bahrain Dinar
+	BIF Currency = "BIF" // Burundi Franc
+	BMD Currency = "BMD" // Bermuda Dollar
+	BND Currency = "BND" // Brunei Darussalam Dollar
+	BOB Currency = "BOB" // Bolivia Boliviano
+	BRL Currency = "BRL" // Brazil Real
+	BSD Currency = "BSD" // Bahamas Dollar
+	BTN Currency = "BTN" // Bhutan Ngultrum
+	BWP Currency = "BWP" // Botswana Pula
+	BYR Currency = "BYR" // Belarus Ruble
+	BZD Currency = "BZD" // Belize Dollar
+	CAD Currency = "CAD" // Canada Dollar
+	CDF Currency = "CDF" // Congo/Kinshasa Franc
+	CHF Currency = "CHF" // Switzerland Franc
+	CLP Currency = "CLP" // Chile Peso
+	CNY Currency = "CNY" // China Yuan Renminbi
+	COP Currency = "COP" // Colombia Peso
+	CRC Currency = "CRC" // Costa Rica Colon
+	CUC Currency = "CUC" // Cuba Convertible Peso
+	CUP Currency = "CUP" // Cuba Peso
+	CVE Currency = "CVE" // Cape Verde Escudo
+	CZK Currency = "CZK" // Czech Republic Koruna
+	DJF Currency = "DJF" // Djibouti Franc
+	DKK Currency = "DKK" // Denmark Krone
+	DOP Currency = "DOP" // Dominican Republic Peso
+	DZD Currency = "DZD" // Algeria Dinar
+	EGP Currency = "EGP" // Egypt Pound
+	ERN Currency = "ERN" // Eritrea Nakfa
+	ETB Currency = "ETB" // Ethiopia Birr
+	EUR Currency = "EUR" // Euro Member Countries
+	FJD Currency = "FJD" // Fiji Dollar
+	FKP Currency = "FKP" // Falkland Islands (Malvinas) Pound
+	GBP Currency = "GBP" // United Kingdom Pound
+	GEL Currency = "GEL" // Georgia Lari
+	GGP Currency = "GGP" // Guernsey Pound
+	GHS Currency = "GHS" // Ghana Cedi
+	GIP Currency = "GIP" // Gibraltar Pound
+	GMD Currency = "GMD" // Gambia Dalasi
+	GNF Currency = "GNF" // Guinea Franc
+	GTQ Currency = "GTQ" // Guatemala Quetzal
+	GYD Currency = "GYD" // Guyana Dollar
+	HKD Currency = "HKD" // Hong Kong Dollar
+	HNL Currency = "HNL" // Honduras Lempira
+	HRK Currency = "HRK" // Croatia Kuna
+	HTG Currency = "HTG" // Haiti Gourde
+	HUF Currency = "HUF" // Hungary Forint
+	IDR Currency = "IDR" // Indonesia Rupiah
+	ILS Currency = "ILS" // Israel Shekel
+	IMP Currency = "IMP" // Isle of Man Pound
+	INR Currency = "INR" // India Rupee
+	IQD Currency = "IQD" // Iraq Dinar
+	IRR Currency = "IRR" // Iran Rial
+	ISK Currency = "ISK" // Iceland Krona
+	JEP Currency = "JEP" // Jersey Pound
+	JMD Currency = "JMD" // Jamaica Dollar
+	JOD Currency = "JOD" // Jordan Dinar
+	JPY Currency = "JPY" // Japan Yen
+	KES Currency = "KES" // Kenya Shilling
+	KGS Currency = "KGS" // Kyrgyzstan Som
+	KHR Currency = "KHR" // Cambodia Riel
+	KMF Currency = "KMF" // Comoros Franc
+	KPW Currency = "KPW" // Korea (North) Won
+	KRW Currency = "KRW" // Korea (South) Won
+	KWD Currency = "KWD" // Kuwait Dinar
+	KYD Currency = "KYD" // Cayman Islands Dollar
+	KZT Currency = "KZT" // Kazakhstan Tenge
+	LAK Currency = "LAK" // Laos Kip
+	LBP Currency = "LBP" // Lebanon Pound
+	LKR Currency = "LKR" // Sri Lanka Rupee
+	LRD Currency = "LRD" // Liberia Dollar
+	LSL Currency = "LSL" // Lesotho Loti
+	LYD Currency = "LYD" // Libya Dinar
+	MAD Currency = "MAD" // Morocco Dirham
+	MDL Currency = "MDL" // Moldova Leu
+	MGA Currency = "MGA" // Madagascar Ariary
+	MKD Currency = "MKD" // Macedonia Denar
+	MMK Currency = "MMK" // Myanmar (Burma) Kyat
+	MNT Currency = "MNT" // Mongolia Tughrik
+	MOP Currency = "MOP" // Macau Pataca
+	MRO Currency = "MRO" // Mauritania Ouguiya
+	MUR Currency = "MUR" // Mauritius Rupee
+	MVR Currency = "MVR" // Maldives (Maldive Islands) Rufiyaa
+	MWK Currency = "MWK" // Malawi Kwacha
+	MXN Currency = "MXN" // Mexico Peso
+	MXV Currency = "MXV" // Mexican Unidad de Inversion (UDI) (funds code)
+	MYR Currency = "MYR" // Malaysia Ringgit
+	MZN Currency = "MZN" // Mozambique Metical
+	NAD Currency = "NAD" // Namibia Dollar
+	NGN Currency = "NGN" // Nigeria Naira
+	NIO Currency = "NIO" // Nicaragua Cordoba
+	NOK Currency = "NOK" // Norway Krone
+	NPR Currency = "NPR" // Nepal Rupee
+	NZD Currency = "NZD" // New Zealand Dollar
+	OMR Currency = "OMR" // Oman Rial
+	PAB Currency = "PAB" // Panama Balboa
+	PEN Currency = "PEN" // Peru Nuevo Sol
+	PGK Currency = "PGK" // Papua New Guinea Kina
+	PHP Currency = "PHP" // Philippines Peso
+	PKR Currency = "PKR" // Pakistan Rupee
+	PLN Currency = "PLN" // Poland Zloty
+	PYG Currency = "PYG" // Paraguay Guarani
+	QAR Currency = "QAR" // Qatar Riyal
+	RON Currency = "RON" // Romania New Leu
+	RSD Currency = "RSD" // Serbia Dinar
+	RUB Currency = "RUB" // Russia Ruble
+	RWF Currency = "RWF" // Rwanda Franc
+	SAR Currency = "SAR" // Saudi Arabia Riyal
+	SBD Currency = "SBD" // Solomon Islands Dollar
+	SCR Currency = "SCR" // Seychelles Rupee
+	SDG Currency = "SDG" // Sudan Pound
+	SEK Currency = "SEK" // Sweden Krona
+	SGD Currency = "SGD" // Singapore Dollar
+	SHP Currency = "SHP" // Saint Helena Pound
+	SLL Currency = "SLL" // Sierra Leone Leone
+	SOS Currency = "SOS" // Somalia Shilling
+	SPL Currency = "SPL" // Seborga Luigino
+	SRD Currency = "SRD" // Suriname Dollar
+	STD Currency = "STD" // São Tomé and Príncipe Dobra
+	SVC Currency = "SVC" // El Salvador Colon
+	SYP Currency = "SYP" // Syria Pound
+	SZL Currency = "SZL" // Swaziland Lilangeni
+	THB Currency = "THB" // Thailand Baht
+	TJS Currency = "TJS" // Tajikistan Somoni
+	TMT Currency = "TMT" // Turkmenistan Manat
+	TND Currency = "TND" // Tunisia Dinar
+	TOP Currency = "TOP" // Tonga Pa'anga
+	TRY Currency = "TRY" // Turkey Lira
+	TTD Currency = "TTD" // Trinidad and Tobago Dollar
+	TVD Currency = "TVD" // Tuvalu Dollar
+	TWD Currency = "TWD" // Taiwan New Dollar
+	TZS Currency = "TZS" // Tanzania Shilling
+	UAH Currency = "UAH" // Ukraine Hryvnia
+	UGX Currency = "UGX" // Uganda Shilling
+	USD Currency = "USD" // United States Dollar
+	UYU Currency = "UYU" // Uruguay Peso
+	UZS Currency = "UZS" // Uzbekistan Som
+	VEF Currency = "VEF" // Venezuela Bolivar
+	VND Currency = "VND" // Viet Nam Dong
+	VUV Currency = "VUV" // Vanuatu Vatu
+	WST Currency = "WST" // Samoa Tala
+	XAF Currency = "XAF" // Communauté Financière Africaine (BEAC) CFA Franc BEAC
+	XCD Currency = "XCD" // East Caribbean Dollar
+	XDR Currency = "XDR" // International Monetary Fund (IMF) Special Drawing Rights
+	XOF Currency = "XOF" // Communauté Financière Africaine (BCEAO) Franc
+	XPF Currency = "XPF" // Comptoirs Français du Pacifique (CFP) Franc
+	YER Currency = "YER" // Yemen Rial
+	ZAR Currency = "ZAR" // South Africa Rand
+	ZMW Currency = "ZMW" // Zambia Kwacha
+	ZWD Currency = "ZWD" // Zimbabwe Dollar
 
 	// Metals
-	XAG Currency = "XAU" // Gold
-	XAU          = "XAG" // Silver
-	XCP          = "XCP" // Copper
-	XPD          = "XPD" // Palladium
-	XPT          = "XPT" // Platinum
+	XAU Currency = "XAU" // Gold
+	XAG Currency = "XAG" // Silver
+	XCP Currency = "XCP" // Copper
+	XPD Currency = "XPD" // Palladium
+	XPT Currency = "XPT" // Platinum
 
 	// Historic currencies
 	CYP Currency = "CYP" // Cypriot pound
-	DEM          = "DEM" // German mark
-	ECS          = "ECS" // Ecuadorian sucre
-	FRF          = "FRF" // French franc
-	IEP          = "IEP" // Irish pound (punt in Irish language)
-	ITL          = "ITL" // Italian lira
-	LTL          = "LTL" // Lithuanian litas
-	LVL          = "LVL" // Latvian lats
-	SIT          = "SIT" // Slovenian tolar
-	ZWL          = "ZWL" // Zimbabwean dollar A/10
+	DEM Currency = "DEM" // German mark
+	ECS Currency = "ECS" // Ecuadorian sucre
+	FRF Currency = "FRF" // French franc
+	IEP Currency = "IEP" // Irish pound (punt in Irish language)
+	ITL Currency = "ITL" // Italian lira
+	LTL Currency = "LTL" // Lithuanian litas
+	LVL Currency = "LVL" // Latvian lats
+	SIT Currency = "SIT" // Slovenian tolar
+	ZWL Currency = "ZWL" // Zimbabwean dollar A/10
 
 	// Unofficial currency codes
 	CNH Currency = "CNH" // Chinese yuan (when traded offshore)
@@ -261,6 +262,7 @@ var currencies = [...]Currency{
 var ErrCurrencyLength = errors.New("Currency should be 3 char long")
 var ErrCurrencyUnknown = errors.New("Currency is unknown")
 var ErrFetchingData = errors.New("Unable to fetch data for date")
+var ErrNoRates = errors.New("No exchange rates published for date")
 
 type ErrNotExist struct {
 	Time     time.Time
@@ -271,6 +273,16 @@ func (err ErrNotExist) Error() string {
 	return fmt.Sprintf("Exchange rate for %s @ %s does not exist", err.Currency, toDate(err.Time))
 }
 
+// ErrInvalidAmount is returned when a string is not a valid amount. See
+// ParseAmount for the accepted formats.
+type ErrInvalidAmount struct {
+	Value string
+}
+
+func (err ErrInvalidAmount) Error() string {
+	return fmt.Sprintf("Invalid amount %q", err.Value)
+}
+
 // ParseCurrency returns the Currency value represented by the string.
 func ParseCurrency(v string) (Currency, error) {
 	if len(v) != 3 {
@@ -294,80 +306,280 @@ type Converter struct {
 	ex *Exchange
 }
 
-// New initializes an Converter
-func New(apiToken string) *Converter {
+// New initializes an Converter using the fixer.io API. If apiToken is empty
+// the token is read from the FIXER_API_TOKEN environment variable.
+// The fixer.io client can be configured with WithHTTPClient and WithBaseURL.
+func New(apiToken string, opts ...Option) *Converter {
 	if apiToken == "" {
 		apiToken = os.Getenv("FIXER_API_TOKEN")
 	}
 
+	return NewWithProvider(NewFixerProvider(apiToken), opts...)
+}
+
+// NewWithProvider initializes an Converter which fetches exchange rates from
+// the given provider.
+func NewWithProvider(p RateProvider, opts ...Option) *Converter {
 	return &Converter{
-		ex: NewExchange(apiToken),
+		ex: NewExchange(p, opts...),
 	}
 }
 
+// Exchange returns the Exchange used by the converter.
+func (c *Converter) Exchange() *Exchange {
+	return c.ex
+}
+
 // Convert converts the decimal value to the given currency.
 func (c *Converter) Convert(value decimal.Decimal, from, to Currency) (decimal.Decimal, error) {
-	return c.genConvert(value, from, to, nil)
+	return c.genConvert(context.Background(), value, from, to, nil)
 }
 
 // ConvertAt converts the decimal value to the given currency using the
 // exchange rate from the date specificied.
 func (c *Converter) ConvertAt(value decimal.Decimal, from, to Currency, at time.Time) (decimal.Decimal, error) {
-	return c.genConvert(value, from, to, &at)
+	return c.genConvert(context.Background(), value, from, to, &at)
+}
+
+// ConvertContext is like Convert but uses ctx for fetching the exchange rates.
+func (c *Converter) ConvertContext(ctx context.Context, value decimal.Decimal, from, to Currency) (decimal.Decimal, error) {
+	return c.genConvert(ctx, value, from, to, nil)
+}
+
+// ConvertAtContext is like ConvertAt but uses ctx for fetching the exchange
+// rates.
+func (c *Converter) ConvertAtContext(ctx context.Context, value decimal.Decimal, from, to Currency, at time.Time) (decimal.Decimal, error) {
+	return c.genConvert(ctx, value, from, to, &at)
+}
+
+// ConvertDetailed converts the decimal value to the given currency and returns
+// the result along with the rate used and its provenance.
+func (c *Converter) ConvertDetailed(value decimal.Decimal, from, to Currency) (Conversion, error) {
+	return c.convert(context.Background(), value, from, to, nil)
+}
+
+// ConvertDetailedAt converts the decimal value to the given currency using
+// the exchange rate from the date specificied and returns the result along
+// with the rate used and its provenance.
+func (c *Converter) ConvertDetailedAt(value decimal.Decimal, from, to Currency, at time.Time) (Conversion, error) {
+	return c.convert(context.Background(), value, from, to, &at)
+}
+
+// ConvertDetailedContext is like ConvertDetailed but uses ctx for fetching
+// the exchange rates.
+func (c *Converter) ConvertDetailedContext(ctx context.Context, value decimal.Decimal, from, to Currency) (Conversion, error) {
+	return c.convert(ctx, value, from, to, nil)
+}
+
+// ConvertDetailedAtContext is like ConvertDetailedAt but uses ctx for fetching
+// the exchange rates.
+func (c *Converter) ConvertDetailedAtContext(ctx context.Context, value decimal.Decimal, from, to Currency, at time.Time) (Conversion, error) {
+	return c.convert(ctx, value, from, to, &at)
+}
+
+// ConvertMoney converts m to the given currency.
+func (c *Converter) ConvertMoney(m Money, to Currency) (Money, error) {
+	return c.genConvertMoney(context.Background(), m, to, nil)
+}
+
+// ConvertMoneyAt converts m to the given currency using the exchange rate from
+// the date specificied.
+func (c *Converter) ConvertMoneyAt(m Money, to Currency, at time.Time) (Money, error) {
+	return c.genConvertMoney(context.Background(), m, to, &at)
+}
+
+// ConvertMoneyContext is like ConvertMoney but uses ctx for fetching the
+// exchange rates.
+func (c *Converter) ConvertMoneyContext(ctx context.Context, m Money, to Currency) (Money, error) {
+	return c.genConvertMoney(ctx, m, to, nil)
+}
+
+// ConvertMoneyAtContext is like ConvertMoneyAt but uses ctx for fetching the
+// exchange rates.
+func (c *Converter) ConvertMoneyAtContext(ctx context.Context, m Money, to Currency, at time.Time) (Money, error) {
+	return c.genConvertMoney(ctx, m, to, &at)
 }
 
 // ConvertString converts the decimal value (represented as a string) to the
-// given currency.
+// given currency. The value is parsed by ParseAmount.
 func (c *Converter) ConvertString(value string, from, to Currency) (decimal.Decimal, error) {
-	v, _ := decimal.NewFromString(value)
-	return c.genConvert(v, from, to, nil)
+	v, err := ParseAmount(value)
+
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return c.genConvert(context.Background(), v, from, to, nil)
 }
 
 // ConvertStringAt converts the decimal value (represented as a string) to the
-// given currency using the exchange rate from the date specificied.
+// given currency using the exchange rate from the date specificied. The value
+// is parsed by ParseAmount.
 func (c *Converter) ConvertStringAt(value string, from, to Currency, at time.Time) (decimal.Decimal, error) {
-	v, _ := decimal.NewFromString(value)
-	return c.genConvert(v, from, to, &at)
+	v, err := ParseAmount(value)
+
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return c.genConvert(context.Background(), v, from, to, &at)
+}
+
+// Rate returns the exchange rate from one currency to another, that is the
+// amount of currency to which one unit of currency from buys, and its inverse.
+// Both are derived from the EUR rates and divided once, so the inverse is not
+// subject to the rounding of rate.
+func (c *Converter) Rate(from, to Currency) (rate, inverse decimal.Decimal, err error) {
+	return c.genRate(context.Background(), from, to, nil)
+}
+
+// RateAt returns the exchange rate from one currency to another and its
+// inverse using the exchange rates from the date specificied.
+func (c *Converter) RateAt(from, to Currency, at time.Time) (rate, inverse decimal.Decimal, err error) {
+	return c.genRate(context.Background(), from, to, &at)
+}
+
+// RateContext is like Rate but uses ctx for fetching the exchange rates.
+func (c *Converter) RateContext(ctx context.Context, from, to Currency) (rate, inverse decimal.Decimal, err error) {
+	return c.genRate(ctx, from, to, nil)
 }
 
-func (c *Converter) genConvert(value decimal.Decimal, from, to Currency, at *time.Time) (decimal.Decimal, error) {
+// RateAtContext is like RateAt but uses ctx for fetching the exchange rates.
+func (c *Converter) RateAtContext(ctx context.Context, from, to Currency, at time.Time) (rate, inverse decimal.Decimal, err error) {
+	return c.genRate(ctx, from, to, &at)
+}
+
+func (c *Converter) genRate(ctx context.Context, from, to Currency, at *time.Time) (rate, inverse decimal.Decimal, err error) {
 	var t time.Time
 
 	if at == nil {
-		t = time.Now().UTC()
+		t = c.ex.opts.clock.Now().UTC()
 	} else {
 		t = *at
 	}
 
-	var eur decimal.Decimal
+	rate, _, err = c.crossRate(ctx, t, from, to)
+
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+
+	inverse, _, err = c.crossRate(ctx, t, to, from)
 
-	if from == EUR {
-		eur = value
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+
+	return rate, inverse, nil
+}
+
+func (c *Converter) genConvert(ctx context.Context, value decimal.Decimal, from, to Currency, at *time.Time) (decimal.Decimal, error) {
+	conv, err := c.convert(ctx, value, from, to, at)
+
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return conv.Result, nil
+}
+
+func (c *Converter) genConvertMoney(ctx context.Context, m Money, to Currency, at *time.Time) (Money, error) {
+	v, err := c.genConvert(ctx, m.Amount, m.Currency, to, at)
+
+	if err != nil {
+		return Money{}, err
+	}
+
+	return NewMoney(v, to), nil
+}
+
+func (c *Converter) convert(ctx context.Context, value decimal.Decimal, from, to Currency, at *time.Time) (Conversion, error) {
+	var t time.Time
+
+	if at == nil {
+		t = c.ex.opts.clock.Now().UTC()
 	} else {
-		fromRate, err := c.ex.Get(t, from)
+		t = *at
+	}
+
+	rate, ref, err := c.crossRate(ctx, t, from, to)
+
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	path := []Currency{from, to}
+
+	if from != EUR && to != EUR && from != to {
+		path = []Currency{from, EUR, to}
+	}
+
+	res := value.Mul(rate)
+
+	if c.ex.opts.round {
+		res = c.ex.opts.rounding.RoundCurrency(res, to)
+	}
+
+	return Conversion{
+		Amount:  value,
+		From:    from,
+		Result:  res,
+		To:      to,
+		Rate:    rate,
+		Path:    path,
+		At:      t,
+		Date:    ref.Date,
+		Source:  ref.Source,
+		Fetched: ref.Fetched,
+	}, nil
+}
+
+// crossRate returns the amount of currency to which one unit of currency from
+// buys at the given date. The rate is derived from the EUR rates of both
+// currencies. It also returns one of the EUR rates used, which tells the
+// provenance of the cross rate.
+func (c *Converter) crossRate(ctx context.Context, t time.Time, from, to Currency) (decimal.Decimal, ExchangeRate, error) {
+	fromRate := ExchangeRate{FromEUR: oneD, ToEUR: oneD}
+	toRate := fromRate
+
+	if from != EUR {
+		rate, err := c.ex.GetContext(ctx, t, from)
 
 		if err != nil {
-			return decimal.Zero, err
+			return decimal.Zero, ExchangeRate{}, err
 		}
 
-		eur = value.Mul(fromRate.ToEUR)
-	}
+		if rate.FromEUR.IsZero() {
+			return decimal.Zero, ExchangeRate{}, ErrNotExist{Currency: from, Time: t}
+		}
 
-	if to == EUR {
-		return eur, nil
+		fromRate = rate
 	}
 
-	toRate, err := c.ex.Get(t, to)
+	if to != EUR {
+		rate, err := c.ex.GetContext(ctx, t, to)
 
-	if err != nil {
-		return decimal.Zero, err
+		if err != nil {
+			return decimal.Zero, ExchangeRate{}, err
+		}
+
+		toRate = rate
 	}
 
-	return eur.Mul(toRate.FromEUR), nil
+	switch {
+	case from == to:
+		return oneD, toRate, nil
+	case from == EUR:
+		return toRate.FromEUR, toRate, nil
+	case to == EUR:
+		return fromRate.ToEUR, fromRate, nil
+	default:
+		return c.ex.opts.div(toRate.FromEUR, fromRate.FromEUR), toRate, nil
+	}
 }
 
 // DefaultConverter is the default Converter and is used by Convert, ConvertAt,
-// ConvertString and ConvertStringAt.
+// ConvertString, ConvertStringAt, Rate and RateAt.
 var DefaultConverter = New("")
 
 // Convert converts the decimal value to the given currency.
@@ -400,3 +612,19 @@ func ConvertString(value string, from, to Currency) (decimal.Decimal, error) {
 func ConvertStringAt(value string, from, to Currency, at time.Time) (decimal.Decimal, error) {
 	return DefaultConverter.ConvertStringAt(value, from, to, at)
 }
+
+// Rate returns the exchange rate from one currency to another and its
+// inverse.
+//
+// Rate is a wrapper for DefaultConverter.Rate.
+func Rate(from, to Currency) (rate, inverse decimal.Decimal, err error) {
+	return DefaultConverter.Rate(from, to)
+}
+
+// RateAt returns the exchange rate from one currency to another and its
+// inverse using the exchange rates from the date specificied.
+//
+// RateAt is a wrapper for DefaultConverter.RateAt.
+func RateAt(from, to Currency, at time.Time) (rate, inverse decimal.Decimal, err error) {
+	return DefaultConverter.RateAt(from, to, at)
+}
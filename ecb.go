@@ -0,0 +1,452 @@
+package currency
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultECBBaseURL is the location of the European Central Bank euro foreign
+// exchange reference rate feeds.
+const DefaultECBBaseURL = "https://www.ecb.europa.eu/stats/eurofxref/"
+
+// ECBProvider is a RateProvider which fetches the euro foreign exchange
+// reference rates directly from the European Central Bank. It does not require
+// an API token.
+//
+// Depending on the age of the requested date the rates are read from the daily,
+// the 90 day or the full history feed. The parsed feeds are kept for CacheTTL,
+// so converting a batch of historical dates downloads the history feed once.
+type ECBProvider struct {
+	// BaseURL is the location of the feeds. If empty DefaultECBBaseURL is used.
+	BaseURL string
+
+	// Client is used to make the requests. If nil http.DefaultClient is
+	// used.
+	Client *http.Client
+
+	// CacheTTL is how long a downloaded feed is used before it is
+	// downloaded again. Zero means DefaultECBCacheTTL, a negative value
+	// disables the cache.
+	CacheTTL time.Duration
+
+	// Clock is used to pick the feed for a date and to expire the cached
+	// feeds. If nil the system clock is used.
+	Clock Clock
+
+	mu    sync.Mutex
+	feeds map[string]*ecbFeedEntry
+}
+
+// DefaultECBCacheTTL is how long an ECBProvider keeps a downloaded feed unless
+// configured otherwise. The ECB updates the feeds once a day, around 16:00
+// CET.
+const DefaultECBCacheTTL = time.Hour
+
+// ecbFeedEntry holds a parsed feed. Its mutex is held while the feed is
+// downloaded, so concurrent requests download a feed only once.
+type ecbFeedEntry struct {
+	mu      sync.Mutex
+	days    map[date]Rates
+	fetched time.Time
+}
+
+// NewECBProvider initializes an ECBProvider using DefaultECBBaseURL.
+func NewECBProvider() *ECBProvider {
+	return &ECBProvider{BaseURL: DefaultECBBaseURL}
+}
+
+// Name returns the name of the source used in ExchangeRate.Source.
+func (p *ECBProvider) Name() string {
+	return "ecb"
+}
+
+func (p *ECBProvider) withHTTP(c *http.Client, baseURL string) RateProvider {
+	cp := &ECBProvider{
+		BaseURL:  p.BaseURL,
+		Client:   p.Client,
+		CacheTTL: p.CacheTTL,
+		Clock:    p.Clock,
+	}
+
+	if c != nil {
+		cp.Client = c
+	}
+
+	if baseURL != "" {
+		cp.BaseURL = baseURL
+	}
+
+	return cp
+}
+
+type ecbFeed struct {
+	name   string
+	maxAge time.Duration
+	parse  func(r io.Reader) (map[date]Rates, error)
+}
+
+// ecbFeeds are ordered from the smallest to the largest feed.
+var ecbFeeds = []ecbFeed{
+	{"eurofxref-daily.xml", 7 * 24 * time.Hour, parseECBXML},
+	{"eurofxref-hist-90d.xml", 90 * 24 * time.Hour, parseECBXML},
+	{"eurofxref-hist.zip", 0, parseECBZip},
+}
+
+// FetchRates implements the RateProvider interface. It returns ErrNoRates if
+// the ECB did not publish rates for the date of t.
+func (p *ECBProvider) FetchRates(ctx context.Context, t time.Time) (Rates, error) {
+	key := toDate(t)
+	age := p.clock().Now().Sub(t)
+
+	for _, feed := range ecbFeeds {
+		if feed.maxAge != 0 && age > feed.maxAge {
+			continue
+		}
+
+		days, err := p.fetchFeed(ctx, feed, key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if rates, ok := days[key]; ok {
+			return rates, nil
+		}
+
+		if !beforeFirst(key, days) {
+			return nil, ErrNoRates
+		}
+	}
+
+	return nil, ErrNoRates
+}
+
+// afterLast reports whether key is after the last date in days.
+func afterLast(key date, days map[date]Rates) bool {
+	for d := range days {
+		if d >= key {
+			return false
+		}
+	}
+
+	return true
+}
+
+// beforeFirst reports whether key is before the first date in days.
+func beforeFirst(key date, days map[date]Rates) bool {
+	for d := range days {
+		if d < key {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *ECBProvider) clock() Clock {
+	if p.Clock == nil {
+		return systemClock{}
+	}
+
+	return p.Clock
+}
+
+// ecbRefreshInterval is the minimum time between two downloads of a cached feed
+// to look for a date later than the feed's last date, which limits the
+// downloads for dates the ECB does not publish rates for, such as weekends.
+const ecbRefreshInterval = time.Minute
+
+// fetchFeed returns the parsed feed, downloading it unless a cached copy is
+// younger than CacheTTL. A cached copy is downloaded again if key is after its
+// last date, as the rates of key may have been published since.
+func (p *ECBProvider) fetchFeed(ctx context.Context, feed ecbFeed, key date) (map[date]Rates, error) {
+	ttl := p.CacheTTL
+
+	if ttl == 0 {
+		ttl = DefaultECBCacheTTL
+	}
+
+	if ttl < 0 {
+		return p.downloadFeed(ctx, feed)
+	}
+
+	p.mu.Lock()
+
+	if p.feeds == nil {
+		p.feeds = make(map[string]*ecbFeedEntry)
+	}
+
+	e, ok := p.feeds[feed.name]
+
+	if !ok {
+		e = new(ecbFeedEntry)
+		p.feeds[feed.name] = e
+	}
+
+	p.mu.Unlock()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := p.clock().Now()
+
+	if age := now.Sub(e.fetched); e.days != nil && age < ttl && (age < ecbRefreshInterval || !afterLast(key, e.days)) {
+		return e.days, nil
+	}
+
+	days, err := p.downloadFeed(ctx, feed)
+
+	if err != nil {
+		return nil, err
+	}
+
+	e.days, e.fetched = days, now
+	return days, nil
+}
+
+func (p *ECBProvider) downloadFeed(ctx context.Context, feed ecbFeed) (map[date]Rates, error) {
+	base := p.BaseURL
+
+	if base == "" {
+		base = DefaultECBBaseURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(base, "/")+"/"+feed.name, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := httpClient(p.Client).Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		return nil, newHTTPError(r)
+	}
+
+	return feed.parse(r.Body)
+}
+
+type ecbEnvelope struct {
+	Cube struct {
+		Days []struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// parseECBXML parses the eurofxref XML format used by the daily, 90 day and
+// full history feeds.
+func parseECBXML(r io.Reader) (map[date]Rates, error) {
+	var env ecbEnvelope
+
+	if err := xml.NewDecoder(r).Decode(&env); err != nil {
+		return nil, err
+	}
+
+	days := make(map[date]Rates, len(env.Cube.Days))
+
+	for _, day := range env.Cube.Days {
+		t, err := time.Parse("2006-01-02", day.Time)
+
+		if err != nil {
+			return nil, fmt.Errorf("ecb: invalid date %q", day.Time)
+		}
+
+		rates := make(Rates, len(day.Rates))
+
+		for _, rate := range day.Rates {
+			v, err := decimal.NewFromString(rate.Rate)
+
+			if err != nil {
+				return nil, fmt.Errorf("ecb: invalid rate %q for %s", rate.Rate, rate.Currency)
+			}
+
+			rates[Currency(rate.Currency)] = v
+		}
+
+		days[toDate(t)] = rates
+	}
+
+	return days, nil
+}
+
+// parseECBHistory parses a eurofxref file in any of the formats published by
+// the ECB: XML, CSV or a zip archive holding a CSV file.
+func parseECBHistory(r io.Reader) (map[date]Rates, error) {
+	buf, err := ioutil.ReadAll(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(buf, []byte("PK\x03\x04")):
+		return parseECBZipBytes(buf)
+	case bytes.HasPrefix(bytes.TrimSpace(buf), []byte("<")):
+		return parseECBXML(bytes.NewReader(buf))
+	default:
+		return parseECBCSV(bytes.NewReader(buf))
+	}
+}
+
+// parseECBZip parses a zip archive holding a single eurofxref CSV file.
+func parseECBZip(r io.Reader) (map[date]Rates, error) {
+	buf, err := ioutil.ReadAll(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseECBZipBytes(buf)
+}
+
+func parseECBZipBytes(buf []byte) (map[date]Rates, error) {
+	zr, err := zip.NewReader(bytes.NewReader(buf), int64(len(buf)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range zr.File {
+		if !strings.HasSuffix(strings.ToLower(f.Name), ".csv") {
+			continue
+		}
+
+		rc, err := f.Open()
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer rc.Close()
+		return parseECBCSV(rc)
+	}
+
+	return nil, fmt.Errorf("ecb: no csv file in zip archive")
+}
+
+// parseECBCSV parses the eurofxref CSV format. The first column holds the date
+// and the header row names the currency of each following column. Missing
+// rates are given as N/A.
+func parseECBCSV(r io.Reader) (map[date]Rates, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+
+	if err != nil {
+		return nil, err
+	}
+
+	days := make(map[date]Rates)
+
+	for {
+		record, err := cr.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := parseECBCSVDate(record[0])
+
+		if err != nil {
+			return nil, err
+		}
+
+		rates := make(Rates, len(record))
+
+		for i := 1; i < len(record) && i < len(header); i++ {
+			cur := strings.TrimSpace(header[i])
+			value := strings.TrimSpace(record[i])
+
+			if cur == "" || value == "" || value == "N/A" {
+				continue
+			}
+
+			v, err := decimal.NewFromString(value)
+
+			if err != nil {
+				return nil, fmt.Errorf("ecb: invalid rate %q for %s", value, cur)
+			}
+
+			rates[Currency(cur)] = v
+		}
+
+		days[toDate(t)] = rates
+	}
+
+	return days, nil
+}
+
+// parseECBCSVDate parses the dates used in the history (2006-01-02) and daily
+// (02 January 2006) CSV files.
+func parseECBCSVDate(v string) (time.Time, error) {
+	v = strings.TrimSpace(v)
+
+	for _, layout := range []string{"2006-01-02", "02 January 2006"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("ecb: invalid date %q", v)
+}
+
+// HistoryProvider is a RateProvider serving the exchange rates of an ECB
+// eurofxref history file held in memory. It never accesses the network.
+type HistoryProvider struct {
+	days map[date]Rates
+}
+
+// NewHistoryProvider initializes a HistoryProvider with the exchange rates
+// read from r. See Exchange.Load for the supported formats.
+func NewHistoryProvider(r io.Reader) (*HistoryProvider, error) {
+	days, err := parseECBHistory(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &HistoryProvider{days: days}, nil
+}
+
+// FetchRates implements the RateProvider interface. It returns ErrNoRates if
+// the history holds no rates for the date of t.
+func (p *HistoryProvider) FetchRates(ctx context.Context, t time.Time) (Rates, error) {
+	rates, ok := p.days[toDate(t)]
+
+	if !ok {
+		return nil, ErrNoRates
+	}
+
+	return rates, nil
+}
+
+// Name returns the name of the source used in ExchangeRate.Source.
+func (p *HistoryProvider) Name() string {
+	return "ecb-history"
+}
@@ -0,0 +1,272 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package currency
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testECBCSV = `Date,USD,JPY,PLN,CYP,
+2016-09-06,1.1256,114.45,4.3327,N/A,
+2016-09-05,1.1158,115.37,4.3513,N/A,
+2016-09-02,1.1163,115.56,4.3452,N/A,
+`
+
+const testECBXML = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender>
+		<gesmes:name>European Central Bank</gesmes:name>
+	</gesmes:Sender>
+	<Cube>
+		<Cube time='%s'>
+			<Cube currency='USD' rate='1.0921'/>
+			<Cube currency='JPY' rate='158.51'/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func testECBZip(t *testing.T, csv string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("eurofxref-hist.csv")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := w.Write([]byte(csv)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func newTestECBServer(t *testing.T, latest time.Time) *httptest.Server {
+	hist := testECBZip(t, testECBCSV)
+	daily := fmt.Sprintf(testECBXML, latest.Format("2006-01-02"))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/eurofxref-daily.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(daily))
+	})
+	mux.HandleFunc("/eurofxref-hist-90d.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(daily))
+	})
+	mux.HandleFunc("/eurofxref-hist.zip", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(hist)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestECBProvider(t *testing.T) {
+	latest := time.Now().UTC().AddDate(0, 0, -1)
+	srv := newTestECBServer(t, latest)
+	defer srv.Close()
+
+	p := &ECBProvider{BaseURL: srv.URL}
+
+	tests := []struct {
+		at  time.Time
+		cur Currency
+		exp string
+		err error
+	}{
+		{time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC), USD, "1.1256", nil},
+		{time.Date(2016, 9, 2, 0, 1, 0, 0, time.UTC), PLN, "4.3452", nil},
+		{time.Date(2016, 9, 4, 0, 1, 0, 0, time.UTC), USD, "", ErrNoRates},
+		{time.Date(2016, 9, 7, 0, 1, 0, 0, time.UTC), USD, "", ErrNoRates},
+		{latest, JPY, "158.51", nil},
+		{latest.AddDate(0, 0, 1), USD, "", ErrNoRates},
+	}
+
+	for i, test := range tests {
+		rates, err := p.FetchRates(context.Background(), test.at)
+
+		if err != test.err {
+			t.Fatalf("test %d: expect err %v, got %v", i, test.err, err)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if rates[test.cur].String() != test.exp {
+			t.Fatalf("test %d: expect %s, got %s", i, test.exp, rates[test.cur])
+		}
+
+		if _, ok := rates[CYP]; ok {
+			t.Fatalf("test %d: expect N/A rates to be skipped", i)
+		}
+	}
+}
+
+func TestECBProviderStatus(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	p := &ECBProvider{BaseURL: srv.URL}
+
+	if _, err := p.FetchRates(context.Background(), time.Now()); err == nil {
+		t.Fatal("expect error on unexpected status")
+	}
+}
+
+func TestECBProviderOptions(t *testing.T) {
+	srv := newTestECBServer(t, time.Now().UTC().AddDate(0, 0, -1))
+	defer srv.Close()
+
+	p := &ECBProvider{}
+	ex := NewExchange(p, WithBaseURL(srv.URL), WithHTTPClient(srv.Client()))
+
+	rate, err := ex.Get(time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC), USD)
+
+	if err != nil {
+		t.Fatalf("expect nil err, got %v", err)
+	}
+
+	if rate.FromEUR.String() != "1.1256" {
+		t.Fatalf("expect 1.1256, got %s", rate.FromEUR)
+	}
+
+	if p.BaseURL != "" || p.Client != nil {
+		t.Fatal("expect provider not to be modified")
+	}
+}
+
+func TestHistoryProvider(t *testing.T) {
+	p, err := NewHistoryProvider(bytes.NewReader(testECBZip(t, testECBCSV)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rates, err := p.FetchRates(context.Background(), time.Date(2016, 9, 5, 0, 0, 0, 0, time.UTC))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rates[JPY].String() != "115.37" {
+		t.Fatalf("expect 115.37, got %s", rates[JPY])
+	}
+
+	if _, err := p.FetchRates(context.Background(), time.Date(2016, 9, 4, 0, 0, 0, 0, time.UTC)); err != ErrNoRates {
+		t.Fatalf("expect ErrNoRates, got %v", err)
+	}
+}
+
+func TestECBProviderContext(t *testing.T) {
+	block := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer srv.Close()
+	defer close(block)
+
+	cc := NewWithProvider(&ECBProvider{BaseURL: srv.URL})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := cc.ConvertAtContext(ctx, oneD, USD, EUR, time.Now())
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expect context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestECBProviderFeedCache(t *testing.T) {
+	hist := testECBZip(t, testECBCSV)
+	var downloads int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/eurofxref-hist.zip" {
+			http.NotFound(w, r)
+			return
+		}
+
+		downloads++
+		w.Write(hist)
+	}))
+	defer srv.Close()
+
+	// the date is older than 90 days by the clock, so only the history feed
+	// is read.
+	clock := &fakeClock{now: time.Date(2017, 9, 6, 12, 0, 0, 0, time.UTC)}
+	p := &ECBProvider{BaseURL: srv.URL, Clock: clock}
+	cc := NewWithProvider(p, WithClock(clock))
+
+	for i, day := range []int{2, 5, 6} {
+		if _, err := cc.ConvertStringAt("1", USD, EUR, time.Date(2016, 9, day, 0, 1, 0, 0, time.UTC)); err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+	}
+
+	if downloads != 1 {
+		t.Fatalf("expect 1 download, got %d", downloads)
+	}
+
+	clock.Advance(DefaultECBCacheTTL)
+
+	if _, err := p.FetchRates(context.Background(), time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	if downloads != 2 {
+		t.Fatalf("expect the feed to expire, got %d downloads", downloads)
+	}
+}
+
+func TestECBProviderFeedRefresh(t *testing.T) {
+	cet := time.FixedZone("CET", 60*60)
+	var downloads int
+	latest := "2016-09-05"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		fmt.Fprintf(w, testECBXML, latest)
+	}))
+	defer srv.Close()
+
+	clock := &fakeClock{now: time.Date(2016, 9, 6, 15, 30, 0, 0, cet)}
+	p := &ECBProvider{BaseURL: srv.URL, Clock: clock}
+	cc := NewWithProvider(p, WithClock(clock), WithPublication(16*time.Hour, cet))
+
+	if _, err := cc.ConvertString("1", USD, EUR); err != nil {
+		t.Fatal(err)
+	}
+
+	// the day's rates are published at 16:00, within the cache TTL.
+	latest = "2016-09-06"
+	clock.Advance(45 * time.Minute)
+
+	if _, err := cc.ConvertString("1", USD, EUR); err != nil {
+		t.Fatal(err)
+	}
+
+	if downloads != 2 {
+		t.Fatalf("expect 2 downloads, got %d", downloads)
+	}
+
+	// a date after the last date is looked for at most once a minute.
+	if _, err := p.FetchRates(context.Background(), time.Date(2016, 9, 7, 17, 0, 0, 0, cet)); err != ErrNoRates {
+		t.Fatalf("expect ErrNoRates, got %v", err)
+	}
+
+	if downloads != 2 {
+		t.Fatalf("expect 2 downloads, got %d", downloads)
+	}
+}
@@ -1,16 +1,20 @@
 package currency
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"net/http"
+	"io"
+	"os"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/shopspring/decimal"
+	"github.com/simonz05/currency/calendar"
 )
 
-var oneD = decimal.NewFromFloat(1.0)
+var oneD = decimal.New(1, 0)
 
 type date string
 
@@ -18,48 +22,119 @@ func toDate(t time.Time) date {
 	return date(t.Format("20060102"))
 }
 
-func toFixerDate(t time.Time) date {
-	return date(t.Format("2006-01-02"))
+func (d date) time() time.Time {
+	t, _ := time.Parse("20060102", string(d))
+	return t
 }
 
+// ExchangeRate is the exchange rate of a currency against EUR.
 type ExchangeRate struct {
 	FromEUR decimal.Decimal
 	ToEUR   decimal.Decimal
+
+	// Date is the date the rate was published for. It differs from the
+	// requested date if a fallback policy picked the rate of another date.
+	Date time.Time
+
+	// Source names where the rate was obtained from: the provider, the
+	// store or a loaded file. A provider or store names itself through a
+	// Name() string method, otherwise its type name is used.
+	Source string
+
+	// Fetched is when the rate was obtained from its source.
+	Fetched time.Time
+}
+
+// sourceName returns the name of a provider or store.
+func sourceName(v interface{}) string {
+	if n, ok := v.(interface{ Name() string }); ok {
+		return n.Name()
+	}
+
+	return fmt.Sprintf("%T", v)
+}
+
+// Rates maps a currency to the amount of that currency one EUR buys.
+type Rates map[Currency]decimal.Decimal
+
+// RateProvider is the interface implemented by a source of exchange rates.
+//
+// FetchRates returns the EUR reference rates for the date of t. The context
+// controls the cancellation and deadline of any request made by the provider.
+// It's safe to call FetchRates concurrently from multiple go routines.
+type RateProvider interface {
+	FetchRates(ctx context.Context, t time.Time) (Rates, error)
+}
+
+// DatedProvider is implemented by a RateProvider which may answer a request
+// for a date without published rates, such as a weekend, with the rates of an
+// earlier date. FetchDatedRates is like FetchRates but also returns the date
+// the rates were published for, which is used for ExchangeRate.Date.
+type DatedProvider interface {
+	RateProvider
+	FetchDatedRates(ctx context.Context, t time.Time) (Rates, time.Time, error)
 }
 
-// Exchange holds a cache of currency exchange rates.
+// Exchange holds a cache of currency exchange rates. The cache is unbounded
+// and never expires unless configured with WithCacheSize, WithCacheTTL or
+// WithTodayTTL.
 type Exchange struct {
-	cache    map[date]map[Currency]ExchangeRate
-	mux      sync.Mutex
-	apiToken string
+	cache    *rateCache
+	calls    map[date]*call
+	mux      sync.RWMutex
+	provider RateProvider
+	opts     options
 }
 
-// NewExchange initializes a new Exchange.
-func NewExchange(apiToken string) *Exchange {
+// call is an in-flight fetch of the exchange rates for a date.
+type call struct {
+	done chan struct{}
+	day  map[Currency]ExchangeRate
+	err  error
+}
+
+// NewExchange initializes a new Exchange which fetches exchange rates from the
+// given provider. If p is nil only rates added to the cache with Load or
+// LoadFile are available.
+func NewExchange(p RateProvider, opts ...Option) *Exchange {
+	o := newOptions(opts)
+
+	if hp, ok := p.(httpProvider); ok && (o.httpClient != nil || o.baseURL != "") {
+		p = hp.withHTTP(o.httpClient, o.baseURL)
+	}
+
 	return &Exchange{
-		cache:    make(map[date]map[Currency]ExchangeRate),
-		apiToken: apiToken,
+		cache:    newRateCache(o.cacheSize),
+		calls:    make(map[date]*call),
+		provider: p,
+		opts:     o,
 	}
 }
 
 // Get looks for an exchange rate for a given currency and date. It will update
 // the cache it does not contain the exchange rates for the given date. It
 // returns ErrNotExist if the exchange rate for the currency does not exist.
-// It's safe to call Get concurrently from multiple go routines.
+// Dates without published rates are handled according to the policy set with
+// WithFallback.
+//
+// If the provider implements Publisher, or WithPublication is given, the date
+// of t is taken in the publication time zone and rates which are not yet
+// published are replaced by those of the previous business day.
+// It's safe to call Get concurrently from multiple go routines. Lookups of
+// cached dates never wait for a fetch and concurrent lookups of the same
+// missing date share a single fetch.
 func (ex *Exchange) Get(t time.Time, c Currency) (ExchangeRate, error) {
-	ex.mux.Lock()
-	defer ex.mux.Unlock()
-	key := toDate(t)
-	day, ok := ex.cache[key]
-
-	if !ok {
-		err := ex.update(t)
+	return ex.GetContext(context.Background(), t, c)
+}
 
-		if err != nil {
-			return ExchangeRate{}, err
-		}
+// GetContext is like Get but uses ctx for fetching the exchange rates if the
+// cache does not contain them.
+func (ex *Exchange) GetContext(ctx context.Context, t time.Time, c Currency) (ExchangeRate, error) {
+	t = ex.rateTime(t)
+	day, err := ex.resolve(ctx, t)
 
-		day = ex.cache[key]
+	if err != nil {
+		return ExchangeRate{}, err
 	}
 
 	rate, ok := day[c]
@@ -71,182 +146,304 @@ func (ex *Exchange) Get(t time.Time, c Currency) (ExchangeRate, error) {
 	return rate, nil
 }
 
-func (ex *Exchange) update(t time.Time) error {
-	fixerData, err := fetchFixerData(t, ex.apiToken)
+// day returns the exchange rates for the date of t from the cache, fetching
+// them if needed. Only one fetch per date is in flight at a time; other callers
+// wait for it to finish. If the fetch fails because the context of the caller
+// which started it is done, the waiting callers start a new fetch.
+func (ex *Exchange) day(ctx context.Context, t time.Time) (map[Currency]ExchangeRate, error) {
+	key := toDate(t)
+
+	for {
+		now := ex.opts.clock.Now()
+		ex.mux.RLock()
+		day, ok := ex.cache.get(key, now)
+		ex.mux.RUnlock()
+
+		if ok {
+			return day, nil
+		}
+
+		ex.mux.Lock()
+		day, ok = ex.cache.get(key, now)
+
+		if ok {
+			ex.mux.Unlock()
+			return day, nil
+		}
+
+		c, wait := ex.calls[key]
+
+		if !wait {
+			c = &call{done: make(chan struct{})}
+			ex.calls[key] = c
+		}
+
+		ex.mux.Unlock()
+
+		if !wait {
+			ex.doCall(ctx, t, key, c)
+			return c.day, c.err
+		}
+
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if c.err == nil {
+			return c.day, nil
+		}
+
+		if !errors.Is(c.err, context.Canceled) && !errors.Is(c.err, context.DeadlineExceeded) {
+			return nil, c.err
+		}
+	}
+}
+
+func (ex *Exchange) doCall(ctx context.Context, t time.Time, key date, c *call) {
+	defer func() {
+		ex.mux.Lock()
+
+		if c.err == nil {
+			ex.putLocked(key, t, c.day)
+		}
+
+		delete(ex.calls, key)
+		ex.mux.Unlock()
+		close(c.done)
+	}()
+
+	// waiters see ErrFetchingData if update panics.
+	c.err = ErrFetchingData
+	c.day, c.err = ex.update(ctx, t)
+}
+
+// Load populates the cache with the exchange rates read from r, which holds an
+// ECB eurofxref history file as published at
+// https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.zip. The file may
+// be given as CSV, as XML or as the zip archive holding the CSV file. Rates
+// already in the cache for the dates in the file are replaced. The loaded
+// rates are kept regardless of WithCacheSize and WithCacheTTL.
+//
+// Together with an Exchange without a provider Load allows conversions to run
+// without network access.
+func (ex *Exchange) Load(r io.Reader) error {
+	days, err := parseECBHistory(r)
 
 	if err != nil {
 		return err
 	}
 
-	data, err := normalizeFixerData(fixerData)
+	now := ex.opts.clock.Now()
+	ex.mux.Lock()
+
+	for key, rates := range days {
+		ex.cache.pin(key, ex.normalizeRates(key.time(), rates, "file", now))
+	}
+
+	ex.mux.Unlock()
+
+	for key, rates := range days {
+		ex.reportUnknown(key.time(), rates)
+	}
+
+	return nil
+}
+
+// LoadFile populates the cache with the exchange rates read from the named
+// ECB eurofxref history file. See Load for the supported formats.
+func (ex *Exchange) LoadFile(name string) error {
+	f, err := os.Open(name)
 
 	if err != nil {
 		return err
 	}
 
-	ex.cache[toDate(t)] = data
-	return nil
+	defer f.Close()
+	return ex.Load(f)
 }
 
-type fixerCurrencyResponse struct {
-	Base    string `json:"base"`
-	Date    string `json:"date"`
-	Success bool   `json:"success"`
-	Error   struct {
-		Info string `json:"info"`
-	} `json:"error,omitempty"`
-	//Rates interface{} `json:"rate"`
-	Rates struct {
-		AUD float64 `json:"AUD"`
-		BGN float64 `json:"BGN"`
-		BRL float64 `json:"BRL"`
-		CAD float64 `json:"CAD"`
-		CHF float64 `json:"CHF"`
-		CNY float64 `json:"CNY"`
-		CYP float64 `json:"CYP"`
-		CZK float64 `json:"CZK"`
-		DKK float64 `json:"DKK"`
-		EEK float64 `json:"EEK"`
-		EUR float64 `json:"EUR"`
-		GBP float64 `json:"GBP"`
-		HKD float64 `json:"HKD"`
-		HRK float64 `json:"HRK"`
-		HUF float64 `json:"HUF"`
-		IDR float64 `json:"IDR"`
-		ILS float64 `json:"ILS"`
-		INR float64 `json:"INR"`
-		ISK float64 `json:"ISK"`
-		JPY float64 `json:"JPY"`
-		KRW float64 `json:"KRW"`
-		LTL float64 `json:"LTL"`
-		LVL float64 `json:"LVL"`
-		MTL float64 `json:"MTL"`
-		MXN float64 `json:"MXN"`
-		MYR float64 `json:"MYR"`
-		NOK float64 `json:"NOK"`
-		NZD float64 `json:"NZD"`
-		PHP float64 `json:"PHP"`
-		PLN float64 `json:"PLN"`
-		ROL float64 `json:"ROL"`
-		RON float64 `json:"RON"`
-		RUB float64 `json:"RUB"`
-		SEK float64 `json:"SEK"`
-		SGD float64 `json:"SGD"`
-		SIT float64 `json:"SIT"`
-		SKK float64 `json:"SKK"`
-		THB float64 `json:"THB"`
-		TRL float64 `json:"TRL"`
-		TRY float64 `json:"TRY"`
-		USD float64 `json:"USD"`
-		ZAR float64 `json:"ZAR"`
-	} `json:"rates"`
-}
-
-func normalizeFixerData(fixerData *fixerCurrencyResponse) (map[Currency]ExchangeRate, error) {
-	data := make(map[Currency]ExchangeRate)
-
-	add := func(cur Currency, price float64) {
-		fromEUR := decimal.NewFromFloat(price)
-		toEUR := fromEUR
+// putLocked adds the exchange rates for the date of t to the cache. The
+// entry expires after the TTL configured for the date. ex.mux must be held.
+func (ex *Exchange) putLocked(key date, t time.Time, day map[Currency]ExchangeRate) {
+	now := ex.opts.clock.Now()
+	ttl := ex.opts.cacheTTL
 
-		if price != 0 {
-			toEUR = oneD.Div(fromEUR)
-		}
+	if ex.opts.todayTTL > 0 && !ex.past(t) {
+		ttl = ex.opts.todayTTL
+	}
 
-		data[cur] = ExchangeRate{
-			FromEUR: fromEUR,
-			ToEUR:   toEUR,
-		}
+	var expires time.Time
+
+	if ttl > 0 {
+		expires = now.Add(ttl)
 	}
 
-	add(AUD, fixerData.Rates.AUD)
-	add(BGN, fixerData.Rates.BGN)
-	add(BRL, fixerData.Rates.BRL)
-	add(CAD, fixerData.Rates.CAD)
-	add(CHF, fixerData.Rates.CHF)
-	add(CNY, fixerData.Rates.CNY)
-	add(CYP, fixerData.Rates.CYP)
-	add(CZK, fixerData.Rates.CZK)
-	add(DKK, fixerData.Rates.DKK)
-	//add(EUR, fixerData.Rates.EUR)
-	add(GBP, fixerData.Rates.GBP)
-	add(HKD, fixerData.Rates.HKD)
-	add(HRK, fixerData.Rates.HRK)
-	add(HUF, fixerData.Rates.HUF)
-	add(IDR, fixerData.Rates.IDR)
-	add(ILS, fixerData.Rates.ILS)
-	add(INR, fixerData.Rates.INR)
-	add(ISK, fixerData.Rates.ISK)
-	add(JPY, fixerData.Rates.JPY)
-	add(KRW, fixerData.Rates.KRW)
-	add(LTL, fixerData.Rates.LTL)
-	add(LVL, fixerData.Rates.LVL)
-	add(MXN, fixerData.Rates.MXN)
-	add(MYR, fixerData.Rates.MYR)
-	add(NOK, fixerData.Rates.NOK)
-	add(NZD, fixerData.Rates.NZD)
-	add(PHP, fixerData.Rates.PHP)
-	add(PLN, fixerData.Rates.PLN)
-	add(RON, fixerData.Rates.RON)
-	add(RUB, fixerData.Rates.RUB)
-	add(SEK, fixerData.Rates.SEK)
-	add(SGD, fixerData.Rates.SGD)
-	add(SIT, fixerData.Rates.SIT)
-	add(THB, fixerData.Rates.THB)
-	add(TRY, fixerData.Rates.TRY)
-	add(USD, fixerData.Rates.USD)
-	add(ZAR, fixerData.Rates.ZAR)
+	ex.cache.put(key, day, expires, now)
+}
 
-	data[EUR] = ExchangeRate{
-		FromEUR: decimal.NewFromFloat(1.0),
-		ToEUR:   decimal.NewFromFloat(1.0),
+// past reports whether the date of t is before the current date.
+func (ex *Exchange) past(t time.Time) bool {
+	return toDate(t) < toDate(ex.opts.clock.Now().In(t.Location()))
+}
+
+// update returns the exchange rates for the date of t.
+func (ex *Exchange) update(ctx context.Context, t time.Time) (map[Currency]ExchangeRate, error) {
+	rates, published, source, err := ex.loadRates(ctx, t)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return data, nil
+	ex.reportUnknown(t, rates)
+	return ex.normalizeRates(published, rates, source, ex.opts.clock.Now()), nil
 }
 
-func fetchFixerData(t time.Time, apiToken string) (*fixerCurrencyResponse, error) {
-	maxTries := 1
+// loadRates returns the rates for the date of t from the store, see
+// loadStored, or, if the store holds none, from the provider. The fetched
+// rates of past dates are added to the store. A SharedStore receives the rates
+// of all dates and is locked while the rates are fetched. Store errors are
+// reported and otherwise ignored. It also returns the date the rates were
+// published for and the name of the source of the rates.
+func (ex *Exchange) loadRates(ctx context.Context, t time.Time) (Rates, time.Time, string, error) {
+	store := ex.opts.store
+
+	if store == nil {
+		rates, published, err := ex.fetch(ctx, t)
+		return rates, published, sourceName(ex.provider), err
+	}
+
+	rates, published, err := ex.loadStored(ctx, store, t)
+
+	if err == nil {
+		return rates, published, sourceName(store), nil
+	}
+
+	if !errors.Is(err, ErrNoRates) {
+		ex.reportStoreError(t, err)
+	}
+
+	shared, isShared := store.(SharedStore)
+
+	if isShared {
+		unlock, err := shared.Lock(ctx, t)
 
-	for i := 0; i < maxTries; i++ {
-		resp, err := fixerDataRequest(t, apiToken)
+		switch {
+		case err == nil:
+			defer unlock()
 
-		if err != nil {
-			if i+1 == maxTries {
-				return nil, err
+			// another process may have stored the rates while we waited.
+			rates, published, err := ex.loadStored(ctx, store, t)
+
+			if err == nil {
+				return rates, published, sourceName(store), nil
+			}
+
+			if !errors.Is(err, ErrNoRates) {
+				ex.reportStoreError(t, err)
 			}
-		} else {
-			return resp, nil
+		case ctx.Err() != nil:
+			return nil, time.Time{}, "", ctx.Err()
+		default:
+			ex.reportStoreError(t, err)
 		}
+	}
+
+	rates, published, err = ex.fetch(ctx, t)
+
+	if err != nil {
+		return nil, time.Time{}, "", err
+	}
 
-		if i == maxTries {
-			time.Sleep(time.Millisecond * 100 * time.Duration(i))
+	// the rates are stored for the date they were published for, so the
+	// store never holds rates under another date.
+	if isShared || ex.past(published) {
+		if err := store.StoreRates(ctx, published, rates); err != nil {
+			ex.reportStoreError(published, err)
 		}
 	}
 
-	return nil, ErrFetchingData
+	return rates, published, sourceName(ex.provider), nil
 }
 
-func fixerDataRequest(t time.Time, apiToken string) (*fixerCurrencyResponse, error) {
-	url := "http://data.fixer.io/api/" + string(toFixerDate(t)) + "?base=EUR&access_key=" + apiToken
-	r, err := http.Get(url)
+// loadStored returns the rates stored for the date of t and the date they were
+// published for. A DatedProvider answers a date which is not a business day
+// with the rates of the previous business day, which are stored under that
+// date, so they are looked up there as well.
+func (ex *Exchange) loadStored(ctx context.Context, store RateStore, t time.Time) (Rates, time.Time, error) {
+	rates, err := store.LoadRates(ctx, t)
 
-	if err != nil {
-		return nil, err
+	if !errors.Is(err, ErrNoRates) {
+		return rates, t, err
+	}
+
+	if _, ok := ex.provider.(DatedProvider); !ok || calendar.IsBusinessDay(t) {
+		return nil, t, err
 	}
 
-	defer r.Body.Close()
-	dec := json.NewDecoder(r.Body)
-	target := new(fixerCurrencyResponse)
-	err = dec.Decode(target)
+	prev := calendar.PreviousBusinessDay(t)
+	rates, err = store.LoadRates(ctx, prev)
+	return rates, prev, err
+}
 
-	if err != nil {
-		return nil, err
+// reportStoreError passes a store error to the store error function.
+func (ex *Exchange) reportStoreError(t time.Time, err error) {
+	if ex.opts.storeErrFunc != nil {
+		ex.opts.storeErrFunc(t, err)
 	}
+}
+
+// reportUnknown passes the codes in rates not known to ParseCurrency to the
+// unknown currency function.
+func (ex *Exchange) reportUnknown(t time.Time, rates Rates) {
+	if ex.opts.unknownFunc == nil {
+		return
+	}
+
+	var codes []Currency
 
-	if !target.Success {
-		return nil, fmt.Errorf("fixer API err: %s", target.Error.Info)
+	for cur := range rates {
+		if _, err := ParseCurrency(string(cur)); err != nil {
+			codes = append(codes, cur)
+		}
+	}
+
+	if len(codes) == 0 {
+		return
+	}
+
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	ex.opts.unknownFunc(t, codes)
+}
+
+func (ex *Exchange) normalizeRates(t time.Time, rates Rates, source string, fetched time.Time) map[Currency]ExchangeRate {
+	data := make(map[Currency]ExchangeRate, len(rates)+1)
+	day := toDate(t).time()
+
+	for cur, fromEUR := range rates {
+		toEUR := fromEUR
+
+		if !fromEUR.IsZero() {
+			toEUR = ex.opts.div(oneD, fromEUR)
+		}
+
+		data[cur] = ExchangeRate{
+			FromEUR: fromEUR,
+			ToEUR:   toEUR,
+			Date:    day,
+			Source:  source,
+			Fetched: fetched,
+		}
+	}
+
+	data[EUR] = ExchangeRate{
+		FromEUR: oneD,
+		ToEUR:   oneD,
+		Date:    day,
+		Source:  source,
+		Fetched: fetched,
 	}
 
-	return target, nil
+	return data
 }
@@ -0,0 +1,308 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package currency
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type stubProvider struct {
+	mu    sync.Mutex
+	rates map[date]Rates
+	calls int
+}
+
+func (p *stubProvider) FetchRates(ctx context.Context, t time.Time) (Rates, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	rates, ok := p.rates[toDate(t)]
+
+	if !ok {
+		return nil, ErrFetchingData
+	}
+
+	return rates, nil
+}
+
+func newStubProvider() *stubProvider {
+	return &stubProvider{
+		rates: map[date]Rates{
+			"20160906": {
+				USD: decimal.RequireFromString("1.1256"),
+				PLN: decimal.RequireFromString("4.3327"),
+			},
+		},
+	}
+}
+
+func TestExchangeProvider(t *testing.T) {
+	p := newStubProvider()
+	ex := NewExchange(p)
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		rate, err := ex.Get(at, USD)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if rate.FromEUR.String() != "1.1256" {
+			t.Fatalf("expect 1.1256, got %s", rate.FromEUR)
+		}
+	}
+
+	if p.calls != 1 {
+		t.Fatalf("expect 1 provider call, got %d", p.calls)
+	}
+
+	if _, err := ex.Get(at, JPY); !errors.As(err, new(ErrNotExist)) {
+		t.Fatalf("expect ErrNotExist, got %v", err)
+	}
+
+	if _, err := ex.Get(at.AddDate(0, 0, 1), USD); err != ErrFetchingData {
+		t.Fatalf("expect ErrFetchingData, got %v", err)
+	}
+}
+
+func TestConverterWithProvider(t *testing.T) {
+	cc := NewWithProvider(newStubProvider())
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		from  Currency
+		to    Currency
+		exp   string
+	}{
+		{"1.0000", USD, USD, "1.0000"},
+		{"1.0000", USD, EUR, "0.8884"},
+		{"1.0000", EUR, USD, "1.1256"},
+		{"1.0000", PLN, USD, "0.2598"},
+		{"1.0000", PLN, EUR, "0.2308"},
+		{"0", PLN, EUR, "0.0000"},
+	}
+
+	for i, test := range tests {
+		res, err := cc.ConvertStringAt(test.value, test.from, test.to, at)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.StringFixed(4) != test.exp {
+			t.Fatalf("test %d: expect %s, got %s", i, test.exp, res.StringFixed(4))
+		}
+	}
+}
+
+func TestExchangeLoad(t *testing.T) {
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+	name := filepath.Join(t.TempDir(), "eurofxref-hist.zip")
+
+	if err := ioutil.WriteFile(name, testECBZip(t, testECBCSV), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaders := []func(ex *Exchange) error{
+		func(ex *Exchange) error { return ex.Load(strings.NewReader(testECBCSV)) },
+		func(ex *Exchange) error { return ex.LoadFile(name) },
+	}
+
+	for i, load := range loaders {
+		cc := NewWithProvider(nil)
+
+		if err := load(cc.Exchange()); err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+
+		res, err := cc.ConvertStringAt("1.0000", PLN, USD, at)
+
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+
+		if res.StringFixed(4) != "0.2598" {
+			t.Fatalf("test %d: expect 0.2598, got %s", i, res.StringFixed(4))
+		}
+
+		if _, err := cc.ConvertStringAt("1.0000", PLN, USD, at.AddDate(0, 0, 1)); err != ErrFetchingData {
+			t.Fatalf("test %d: expect ErrFetchingData, got %v", i, err)
+		}
+	}
+}
+
+func TestExchangeUnknownCurrency(t *testing.T) {
+	p := newStubProvider()
+	p.rates["20160906"]["MTL"] = decimal.RequireFromString("0.4293")
+	p.rates["20160906"]["BTC"] = decimal.RequireFromString("0.001853")
+
+	var unknown []Currency
+	ex := NewExchange(p, WithUnknownCurrencyFunc(func(t time.Time, codes []Currency) {
+		unknown = codes
+	}))
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+	rate, err := ex.Get(at, "MTL")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rate.FromEUR.String() != "0.4293" {
+		t.Fatalf("expect 0.4293, got %s", rate.FromEUR)
+	}
+
+	if len(unknown) != 2 || unknown[0] != "BTC" || unknown[1] != "MTL" {
+		t.Fatalf("expect [BTC MTL] reported, got %v", unknown)
+	}
+}
+
+func TestExchangeDivisionPrecision(t *testing.T) {
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+	cc := NewWithProvider(newStubProvider(), WithDivisionPrecision(6), WithDivisionRounding(RoundDown))
+	rate, err := cc.Exchange().Get(at, USD)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rate.ToEUR.String() != "0.888415" {
+		t.Fatalf("expect 0.888415, got %s", rate.ToEUR)
+	}
+
+	res, err := cc.ConvertStringAt("1", PLN, USD, at)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.String() != "0.259791" {
+		t.Fatalf("expect 0.259791, got %s", res)
+	}
+}
+
+// blockingProvider blocks fetches of the rates for date 2016-09-07 until
+// release is closed.
+type blockingProvider struct {
+	*stubProvider
+	release chan struct{}
+}
+
+func (p *blockingProvider) FetchRates(ctx context.Context, t time.Time) (Rates, error) {
+	if toDate(t) == "20160907" {
+		select {
+		case <-p.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return p.stubProvider.FetchRates(ctx, t)
+}
+
+func TestExchangeConcurrentFetch(t *testing.T) {
+	stub := newStubProvider()
+	stub.rates["20160907"] = Rates{USD: decimal.RequireFromString("1.1241")}
+	p := &blockingProvider{stubProvider: stub, release: make(chan struct{})}
+	ex := NewExchange(p)
+	cached := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+	slow := cached.AddDate(0, 0, 1)
+
+	if _, err := ex.Get(cached, USD); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := ex.Get(slow, USD)
+			errs <- err
+		}()
+	}
+
+	// a lookup of a cached date must not wait for the pending fetch.
+	done := make(chan struct{})
+
+	go func() {
+		ex.Get(cached, USD)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lookup of cached date blocked by pending fetch")
+	}
+
+	// a waiter giving up does not affect the other callers.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := ex.GetContext(ctx, slow, USD); err != context.Canceled {
+		t.Fatalf("expect context.Canceled, got %v", err)
+	}
+
+	close(p.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if stub.calls != 2 {
+		t.Fatalf("expect 2 provider calls, got %d", stub.calls)
+	}
+}
+
+func TestConverterRate(t *testing.T) {
+	cc := NewWithProvider(newStubProvider(), WithDivisionPrecision(6), WithDivisionRounding(RoundDown))
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+
+	tests := []struct {
+		from    Currency
+		to      Currency
+		rate    string
+		inverse string
+	}{
+		{USD, USD, "1", "1"},
+		{EUR, USD, "1.1256", "0.888415"},
+		{USD, EUR, "0.888415", "1.1256"},
+		{PLN, USD, "0.259791", "3.849235"},
+		{USD, PLN, "3.849235", "0.259791"},
+	}
+
+	for i, test := range tests {
+		rate, inverse, err := cc.RateAt(test.from, test.to, at)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if rate.String() != test.rate || inverse.String() != test.inverse {
+			t.Fatalf("test %d: expect %s/%s, got %s/%s", i, test.rate, test.inverse, rate, inverse)
+		}
+	}
+
+	if _, _, err := cc.RateAt(USD, JPY, at); !errors.As(err, new(ErrNotExist)) {
+		t.Fatalf("expect ErrNotExist, got %v", err)
+	}
+}
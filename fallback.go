@@ -0,0 +1,99 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/simonz05/currency/calendar"
+)
+
+// Fallback specifies which exchange rates an Exchange uses for a date the
+// rates were not published for, such as a weekend or a holiday.
+type Fallback int
+
+const (
+	// FallbackNone leaves dates without published rates to the provider.
+	// Some providers return the rates of the previous business day, others
+	// return ErrNoRates.
+	FallbackNone Fallback = iota
+	// FallbackPrevious uses the rates of the last business day before the
+	// date. Business days follow the TARGET2 calendar, see package
+	// calendar.
+	FallbackPrevious
+	// FallbackNext uses the rates of the first business day after the date.
+	FallbackNext
+	// FallbackStrict returns ErrNoRates for dates without published rates.
+	FallbackStrict
+)
+
+// resolve returns the exchange rates for the date of t, applying the fallback
+// policy if no rates were published for the date. Dates which are not TARGET2
+// business days are skipped without asking the provider. The rates picked for
+// another date are cached for the date of t as well, so later lookups of t
+// are served from the cache.
+func (ex *Exchange) resolve(ctx context.Context, t time.Time) (map[Currency]ExchangeRate, error) {
+	f := ex.opts.fallback
+
+	if f == FallbackNone {
+		return ex.day(ctx, t)
+	}
+
+	if day, ok := ex.cached(t); ok {
+		return day, nil
+	}
+
+	if calendar.IsBusinessDay(t) {
+		day, err := ex.day(ctx, t)
+
+		if f == FallbackStrict || !errors.Is(err, ErrNoRates) {
+			return day, err
+		}
+	} else if f == FallbackStrict {
+		return nil, ErrNoRates
+	}
+
+	step := -1
+
+	if f == FallbackNext {
+		step = 1
+	}
+
+	today := toDate(ex.opts.clock.Now().In(t.Location()))
+
+	for i := 1; i <= ex.opts.fallbackDays; i++ {
+		d := t.AddDate(0, 0, i*step)
+
+		if step > 0 && toDate(d) > today {
+			break
+		}
+
+		if !calendar.IsBusinessDay(d) {
+			continue
+		}
+
+		day, err := ex.day(ctx, d)
+
+		if errors.Is(err, ErrNoRates) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		ex.mux.Lock()
+		ex.putLocked(toDate(t), t, day)
+		ex.mux.Unlock()
+		return day, nil
+	}
+
+	return nil, ErrNoRates
+}
+
+// cached returns the exchange rates cached for the date of t.
+func (ex *Exchange) cached(t time.Time) (map[Currency]ExchangeRate, bool) {
+	ex.mux.RLock()
+	defer ex.mux.RUnlock()
+	return ex.cache.get(toDate(t), ex.opts.clock.Now())
+}
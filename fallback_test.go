@@ -0,0 +1,82 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package currency
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExchangeFallback(t *testing.T) {
+	p, err := NewHistoryProvider(strings.NewReader(testECBCSV))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := func(d int) time.Time {
+		return time.Date(2016, 9, d, 12, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		fallback Fallback
+		maxDays  int
+		at       time.Time
+		exp      time.Time
+		err      error
+	}{
+		{FallbackNone, 7, day(4), time.Time{}, ErrNoRates},
+		{FallbackNone, 7, day(5), day(5), nil},
+		{FallbackPrevious, 7, day(4), day(2), nil},
+		{FallbackPrevious, 7, day(7), day(6), nil},
+		{FallbackPrevious, 1, day(4), time.Time{}, ErrNoRates},
+		{FallbackNext, 7, day(3), day(5), nil},
+		{FallbackNext, 3, day(7), time.Time{}, ErrNoRates},
+		{FallbackStrict, 7, day(4), time.Time{}, ErrNoRates},
+		{FallbackStrict, 7, day(6), day(6), nil},
+	}
+
+	for i, test := range tests {
+		ex := NewExchange(p, WithFallback(test.fallback, test.maxDays))
+
+		// the second lookup is served by the cache.
+		for j := 0; j < 2; j++ {
+			rate, err := ex.Get(test.at, USD)
+
+			if err != test.err {
+				t.Fatalf("test %d: expect err %v, got %v", i, test.err, err)
+			}
+
+			if err == nil && !rate.Date.Equal(toDate(test.exp).time()) {
+				t.Fatalf("test %d: expect rate of %s, got %s", i, toDate(test.exp), toDate(rate.Date))
+			}
+		}
+	}
+}
+
+func TestExchangeFallbackCached(t *testing.T) {
+	friday := time.Date(2016, 9, 2, 0, 0, 0, 0, time.UTC)
+	saturday := time.Date(2016, 9, 3, 12, 0, 0, 0, time.UTC)
+	p := newDatesStubProvider(friday)
+	clock := &fakeClock{now: time.Date(2016, 9, 7, 12, 0, 0, 0, time.UTC)}
+	ex := NewExchange(p, WithClock(clock), WithFallback(FallbackPrevious, 7), WithCacheSize(1))
+
+	for i := 0; i < 3; i++ {
+		rate, err := ex.Get(saturday, USD)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !rate.Date.Equal(friday) {
+			t.Fatalf("expect date %s, got %s", friday, rate.Date)
+		}
+	}
+
+	if p.calls != 1 {
+		t.Fatalf("expect 1 provider call, got %d", p.calls)
+	}
+}
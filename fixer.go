@@ -0,0 +1,171 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultFixerBaseURL is the location of the fixer.io API.
+const DefaultFixerBaseURL = "http://data.fixer.io/api/"
+
+func toFixerDate(t time.Time) date {
+	return date(t.Format("2006-01-02"))
+}
+
+// FixerProvider is a RateProvider which fetches the European Central Bank
+// reference rates through the fixer.io API.
+type FixerProvider struct {
+	APIToken string
+
+	// BaseURL is the location of the API. If empty DefaultFixerBaseURL is
+	// used.
+	BaseURL string
+
+	// Client is used to make the requests. If nil http.DefaultClient is
+	// used.
+	Client *http.Client
+}
+
+// NewFixerProvider initializes a FixerProvider using the given fixer.io API
+// token.
+func NewFixerProvider(apiToken string) *FixerProvider {
+	return &FixerProvider{
+		APIToken: apiToken,
+		BaseURL:  DefaultFixerBaseURL,
+	}
+}
+
+// httpProvider is implemented by the providers configured by WithHTTPClient
+// and WithBaseURL. withHTTP returns a copy of the provider using the given
+// client and base URL unless they are nil or empty.
+type httpProvider interface {
+	withHTTP(c *http.Client, baseURL string) RateProvider
+}
+
+func (p *FixerProvider) withHTTP(c *http.Client, baseURL string) RateProvider {
+	cp := *p
+
+	if c != nil {
+		cp.Client = c
+	}
+
+	if baseURL != "" {
+		cp.BaseURL = baseURL
+	}
+
+	return &cp
+}
+
+// FetchRates implements the RateProvider interface.
+func (p *FixerProvider) FetchRates(ctx context.Context, t time.Time) (Rates, error) {
+	rates, _, err := p.FetchDatedRates(ctx, t)
+	return rates, err
+}
+
+// FetchDatedRates implements the DatedProvider interface. fixer.io answers a
+// request for a weekend or a holiday with the rates of the previous business
+// day, whose date is returned.
+func (p *FixerProvider) FetchDatedRates(ctx context.Context, t time.Time) (Rates, time.Time, error) {
+	fixerData, err := p.fixerDataRequest(ctx, t)
+
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	rates, err := normalizeFixerData(fixerData)
+
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	published := t
+
+	if fixerData.Date != "" {
+		published, err = time.Parse("2006-01-02", fixerData.Date)
+
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("fixer API err: invalid date %q", fixerData.Date)
+		}
+	}
+
+	return rates, published, nil
+}
+
+type fixerCurrencyResponse struct {
+	Base    string `json:"base"`
+	Date    string `json:"date"`
+	Success bool   `json:"success"`
+	Error   struct {
+		Info string `json:"info"`
+	} `json:"error,omitempty"`
+	// Rates are decoded directly into decimals to keep the published
+	// figures exact.
+	Rates Rates `json:"rates"`
+}
+
+func normalizeFixerData(fixerData *fixerCurrencyResponse) (Rates, error) {
+	if fixerData.Rates == nil {
+		return make(Rates), nil
+	}
+
+	return fixerData.Rates, nil
+}
+
+func (p *FixerProvider) fixerDataRequest(ctx context.Context, t time.Time) (*fixerCurrencyResponse, error) {
+	base := p.BaseURL
+
+	if base == "" {
+		base = DefaultFixerBaseURL
+	}
+
+	u := strings.TrimSuffix(base, "/") + "/" + string(toFixerDate(t)) + "?base=EUR&access_key=" + url.QueryEscape(p.APIToken)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := httpClient(p.Client).Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		return nil, newHTTPError(r)
+	}
+
+	dec := json.NewDecoder(r.Body)
+	target := new(fixerCurrencyResponse)
+	err = dec.Decode(target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !target.Success {
+		return nil, fmt.Errorf("fixer API err: %s", target.Error.Info)
+	}
+
+	return target, nil
+}
+
+func httpClient(c *http.Client) *http.Client {
+	if c == nil {
+		return http.DefaultClient
+	}
+
+	return c
+}
+
+// Name returns the name of the source used in ExchangeRate.Source.
+func (p *FixerProvider) Name() string {
+	return "fixer"
+}
@@ -0,0 +1,142 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testFixerJSON = `{
+	"success": true,
+	"historical": true,
+	"date": "2016-09-06",
+	"timestamp": 1473206399,
+	"base": "EUR",
+	"rates": {
+		"EUR": 1,
+		"USD": 1.1256,
+		"PLN": 4.3327,
+		"MTL": 0.4293,
+		"BTC": 0.001853,
+		"IDR": 14756.123456789012345678
+	}
+}`
+
+func TestNormalizeFixerData(t *testing.T) {
+	var resp fixerCurrencyResponse
+
+	if err := json.Unmarshal([]byte(testFixerJSON), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	rates, err := normalizeFixerData(&resp)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rates) != 6 {
+		t.Fatalf("expect 6 rates, got %d", len(rates))
+	}
+
+	for cur, exp := range map[Currency]string{
+		USD:   "1.1256",
+		"MTL": "0.4293",
+		"BTC": "0.001853",
+		IDR:   "14756.123456789012345678",
+	} {
+		if rates[cur].String() != exp {
+			t.Fatalf("%s: expect %s, got %s", cur, exp, rates[cur])
+		}
+	}
+}
+
+func TestFixerProviderOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/2016-09-06" || r.URL.Query().Get("access_key") != "token" {
+			w.Write([]byte(`{"success": false, "error": {"info": "invalid request"}}`))
+			return
+		}
+
+		w.Write([]byte(testFixerJSON))
+	}))
+	defer srv.Close()
+
+	cc := New("token", WithBaseURL(srv.URL+"/api/"), WithHTTPClient(srv.Client()))
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+	res, err := cc.ConvertStringAt("1.0000", PLN, USD, at)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.StringFixed(4) != "0.2598" {
+		t.Fatalf("expect 0.2598, got %s", res.StringFixed(4))
+	}
+
+	if _, err := cc.ConvertStringAt("1.0000", PLN, USD, at.AddDate(0, 0, 1)); err == nil {
+		t.Fatal("expect fixer API error")
+	}
+}
+
+func TestFixerProviderPublicationDate(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(strings.Replace(testFixerJSON, "2016-09-06", "2016-09-09", 1)))
+	}))
+	defer srv.Close()
+
+	s := NewFileStore(t.TempDir())
+	clock := &fakeClock{now: time.Date(2016, 9, 12, 12, 0, 0, 0, time.UTC)}
+	ex := NewExchange(&FixerProvider{APIToken: "token", BaseURL: srv.URL}, WithStore(s), WithClock(clock))
+
+	// fixer.io answers a request for a Saturday with the rates of Friday.
+	saturday := time.Date(2016, 9, 10, 12, 0, 0, 0, time.UTC)
+	rate, err := ex.Get(saturday, USD)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := time.Date(2016, 9, 9, 0, 0, 0, 0, time.UTC); !rate.Date.Equal(exp) {
+		t.Fatalf("expect date %s, got %s", exp, rate.Date)
+	}
+
+	if _, err := s.LoadRates(context.Background(), saturday); err != ErrNoRates {
+		t.Fatalf("expect no rates stored for the requested date, got %v", err)
+	}
+
+	if _, err := s.LoadRates(context.Background(), rate.Date); err != nil {
+		t.Fatalf("expect rates stored for the publication date, got %v", err)
+	}
+
+	// after a restart the rates of the weekend are found in the store under
+	// the previous business day.
+	ex = NewExchange(&FixerProvider{APIToken: "token", BaseURL: srv.URL}, WithStore(s), WithClock(clock))
+	sunday := saturday.AddDate(0, 0, 1)
+
+	for _, at := range []time.Time{saturday, sunday} {
+		rate, err := ex.Get(at, USD)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !rate.Date.Equal(time.Date(2016, 9, 9, 0, 0, 0, 0, time.UTC)) || rate.Source != "file" {
+			t.Fatalf("expect stored rates of 2016-09-09, got %s from %s", rate.Date, rate.Source)
+		}
+	}
+
+	if requests != 1 {
+		t.Fatalf("expect 1 request, got %d", requests)
+	}
+}
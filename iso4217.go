@@ -0,0 +1,253 @@
+package currency
+
+// CurrencyInfo holds the ISO 4217 data of a currency.
+type CurrencyInfo struct {
+	Code Currency
+
+	// Numeric is the three-digit ISO 4217 numeric code. It is empty for
+	// codes not assigned by ISO 4217, such as GGP or CNH.
+	Numeric string
+
+	// MinorUnits is the number of decimal places of the minor unit, e.g. 2
+	// for USD, 0 for JPY and 3 for BHD. It is -1 where a minor unit does
+	// not apply, such as for precious metals and XDR.
+	MinorUnits int
+
+	// Name is the official English name, or the common name for codes not
+	// assigned by ISO 4217.
+	Name string
+
+	// Withdrawn reports whether the currency has been withdrawn from use,
+	// e.g. replaced by the euro or redenominated.
+	Withdrawn bool
+}
+
+var currencyInfos = [...]CurrencyInfo{
+	{AED, "784", 2, "UAE Dirham", false},
+	{AFN, "971", 2, "Afghani", false},
+	{ALL, "008", 2, "Lek", false},
+	{AMD, "051", 2, "Armenian Dram", false},
+	{ANG, "532", 2, "Netherlands Antillean Guilder", true},
+	{AOA, "973", 2, "Kwanza", false},
+	{ARS, "032", 2, "Argentine Peso", false},
+	{AUD, "036", 2, "Australian Dollar", false},
+	{AWG, "533", 2, "Aruban Florin", false},
+	{AZN, "944", 2, "Azerbaijan Manat", false},
+	{BAM, "977", 2, "Convertible Mark", false},
+	{BBD, "052", 2, "Barbados Dollar", false},
+	{BDT, "050", 2, "Taka", false},
+	{BGN, "975", 2, "Bulgarian Lev", true},
+	{BHD, "048", 3, "Bahraini Dinar", false},
+	{BIF, "108", 0, "Burundi Franc", false},
+	{BMD, "060", 2, "Bermudian Dollar", false},
+	{BND, "096", 2, "Brunei Dollar", false},
+	{BOB, "068", 2, "Boliviano", false},
+	{BRL, "986", 2, "Brazilian Real", false},
+	{BSD, "044", 2, "Bahamian Dollar", false},
+	{BTN, "064", 2, "Ngultrum", false},
+	{BWP, "072", 2, "Pula", false},
+	{BYR, "974", 0, "Belarusian Ruble", true},
+	{BZD, "084", 2, "Belize Dollar", false},
+	{CAD, "124", 2, "Canadian Dollar", false},
+	{CDF, "976", 2, "Congolese Franc", false},
+	{CHF, "756", 2, "Swiss Franc", false},
+	{CLP, "152", 0, "Chilean Peso", false},
+	{CNY, "156", 2, "Yuan Renminbi", false},
+	{COP, "170", 2, "Colombian Peso", false},
+	{CRC, "188", 2, "Costa Rican Colon", false},
+	{CUC, "931", 2, "Peso Convertible", false},
+	{CUP, "192", 2, "Cuban Peso", false},
+	{CVE, "132", 2, "Cabo Verde Escudo", false},
+	{CZK, "203", 2, "Czech Koruna", false},
+	{DJF, "262", 0, "Djibouti Franc", false},
+	{DKK, "208", 2, "Danish Krone", false},
+	{DOP, "214", 2, "Dominican Peso", false},
+	{DZD, "012", 2, "Algerian Dinar", false},
+	{EGP, "818", 2, "Egyptian Pound", false},
+	{ERN, "232", 2, "Nakfa", false},
+	{ETB, "230", 2, "Ethiopian Birr", false},
+	{EUR, "978", 2, "Euro", false},
+	{FJD, "242", 2, "Fiji Dollar", false},
+	{FKP, "238", 2, "Falkland Islands Pound", false},
+	{GBP, "826", 2, "Pound Sterling", false},
+	{GEL, "981", 2, "Lari", false},
+	{GGP, "", 2, "Guernsey Pound", false},
+	{GHS, "936", 2, "Ghana Cedi", false},
+	{GIP, "292", 2, "Gibraltar Pound", false},
+	{GMD, "270", 2, "Dalasi", false},
+	{GNF, "324", 0, "Guinean Franc", false},
+	{GTQ, "320", 2, "Quetzal", false},
+	{GYD, "328", 2, "Guyana Dollar", false},
+	{HKD, "344", 2, "Hong Kong Dollar", false},
+	{HNL, "340", 2, "Lempira", false},
+	{HRK, "191", 2, "Kuna", true},
+	{HTG, "332", 2, "Gourde", false},
+	{HUF, "348", 2, "Forint", false},
+	{IDR, "360", 2, "Rupiah", false},
+	{ILS, "376", 2, "New Israeli Sheqel", false},
+	{IMP, "", 2, "Manx Pound", false},
+	{INR, "356", 2, "Indian Rupee", false},
+	{IQD, "368", 3, "Iraqi Dinar", false},
+	{IRR, "364", 2, "Iranian Rial", false},
+	{ISK, "352", 0, "Iceland Krona", false},
+	{JEP, "", 2, "Jersey Pound", false},
+	{JMD, "388", 2, "Jamaican Dollar", false},
+	{JOD, "400", 3, "Jordanian Dinar", false},
+	{JPY, "392", 0, "Yen", false},
+	{KES, "404", 2, "Kenyan Shilling", false},
+	{KGS, "417", 2, "Som", false},
+	{KHR, "116", 2, "Riel", false},
+	{KMF, "174", 0, "Comorian Franc", false},
+	{KPW, "408", 2, "North Korean Won", false},
+	{KRW, "410", 0, "Won", false},
+	{KWD, "414", 3, "Kuwaiti Dinar", false},
+	{KYD, "136", 2, "Cayman Islands Dollar", false},
+	{KZT, "398", 2, "Tenge", false},
+	{LAK, "418", 2, "Lao Kip", false},
+	{LBP, "422", 2, "Lebanese Pound", false},
+	{LKR, "144", 2, "Sri Lanka Rupee", false},
+	{LRD, "430", 2, "Liberian Dollar", false},
+	{LSL, "426", 2, "Loti", false},
+	{LYD, "434", 3, "Libyan Dinar", false},
+	{MAD, "504", 2, "Moroccan Dirham", false},
+	{MDL, "498", 2, "Moldovan Leu", false},
+	{MGA, "969", 2, "Malagasy Ariary", false},
+	{MKD, "807", 2, "Denar", false},
+	{MMK, "104", 2, "Kyat", false},
+	{MNT, "496", 2, "Tugrik", false},
+	{MOP, "446", 2, "Pataca", false},
+	{MRO, "478", 2, "Ouguiya", true},
+	{MUR, "480", 2, "Mauritius Rupee", false},
+	{MVR, "462", 2, "Rufiyaa", false},
+	{MWK, "454", 2, "Malawi Kwacha", false},
+	{MXN, "484", 2, "Mexican Peso", false},
+	{MXV, "979", 2, "Mexican Unidad de Inversion (UDI)", false},
+	{MYR, "458", 2, "Malaysian Ringgit", false},
+	{MZN, "943", 2, "Mozambique Metical", false},
+	{NAD, "516", 2, "Namibia Dollar", false},
+	{NGN, "566", 2, "Naira", false},
+	{NIO, "558", 2, "Cordoba Oro", false},
+	{NOK, "578", 2, "Norwegian Krone", false},
+	{NPR, "524", 2, "Nepalese Rupee", false},
+	{NZD, "554", 2, "New Zealand Dollar", false},
+	{OMR, "512", 3, "Rial Omani", false},
+	{PAB, "590", 2, "Balboa", false},
+	{PEN, "604", 2, "Sol", false},
+	{PGK, "598", 2, "Kina", false},
+	{PHP, "608", 2, "Philippine Peso", false},
+	{PKR, "586", 2, "Pakistan Rupee", false},
+	{PLN, "985", 2, "Zloty", false},
+	{PYG, "600", 0, "Guarani", false},
+	{QAR, "634", 2, "Qatari Rial", false},
+	{RON, "946", 2, "Romanian Leu", false},
+	{RSD, "941", 2, "Serbian Dinar", false},
+	{RUB, "643", 2, "Russian Ruble", false},
+	{RWF, "646", 0, "Rwanda Franc", false},
+	{SAR, "682", 2, "Saudi Riyal", false},
+	{SBD, "090", 2, "Solomon Islands Dollar", false},
+	{SCR, "690", 2, "Seychelles Rupee", false},
+	{SDG, "938", 2, "Sudanese Pound", false},
+	{SEK, "752", 2, "Swedish Krona", false},
+	{SGD, "702", 2, "Singapore Dollar", false},
+	{SHP, "654", 2, "Saint Helena Pound", false},
+	{SLL, "694", 2, "Leone", true},
+	{SOS, "706", 2, "Somali Shilling", false},
+	{SPL, "", 2, "Seborga Luigino", false},
+	{SRD, "968", 2, "Surinam Dollar", false},
+	{STD, "678", 2, "Dobra", true},
+	{SVC, "222", 2, "El Salvador Colon", false},
+	{SYP, "760", 2, "Syrian Pound", false},
+	{SZL, "748", 2, "Lilangeni", false},
+	{THB, "764", 2, "Baht", false},
+	{TJS, "972", 2, "Somoni", false},
+	{TMT, "934", 2, "Turkmenistan New Manat", false},
+	{TND, "788", 3, "Tunisian Dinar", false},
+	{TOP, "776", 2, "Pa'anga", false},
+	{TRY, "949", 2, "Turkish Lira", false},
+	{TTD, "780", 2, "Trinidad and Tobago Dollar", false},
+	{TVD, "", 2, "Tuvaluan Dollar", false},
+	{TWD, "901", 2, "New Taiwan Dollar", false},
+	{TZS, "834", 2, "Tanzanian Shilling", false},
+	{UAH, "980", 2, "Hryvnia", false},
+	{UGX, "800", 0, "Uganda Shilling", false},
+	{USD, "840", 2, "US Dollar", false},
+	{UYU, "858", 2, "Peso Uruguayo", false},
+	{UZS, "860", 2, "Uzbekistan Sum", false},
+	{VEF, "937", 2, "Bolívar", true},
+	{VND, "704", 0, "Dong", false},
+	{VUV, "548", 0, "Vatu", false},
+	{WST, "882", 2, "Tala", false},
+	{XAF, "950", 0, "CFA Franc BEAC", false},
+	{XCD, "951", 2, "East Caribbean Dollar", false},
+	{XDR, "960", -1, "SDR (Special Drawing Right)", false},
+	{XOF, "952", 0, "CFA Franc BCEAO", false},
+	{XPF, "953", 0, "CFP Franc", false},
+	{YER, "886", 2, "Yemeni Rial", false},
+	{ZAR, "710", 2, "Rand", false},
+	{ZMW, "967", 2, "Zambian Kwacha", false},
+	{ZWD, "716", 2, "Zimbabwe Dollar", true},
+	{XAU, "959", -1, "Gold", false},
+	{XAG, "961", -1, "Silver", false},
+	{XCP, "", -1, "Copper", false},
+	{XPD, "964", -1, "Palladium", false},
+	{XPT, "962", -1, "Platinum", false},
+	{CYP, "196", 2, "Cyprus Pound", true},
+	{DEM, "276", 2, "Deutsche Mark", true},
+	{ECS, "218", 0, "Sucre", true},
+	{FRF, "250", 2, "French Franc", true},
+	{IEP, "372", 2, "Irish Pound", true},
+	{ITL, "380", 0, "Italian Lira", true},
+	{LTL, "440", 2, "Lithuanian Litas", true},
+	{LVL, "428", 2, "Latvian Lats", true},
+	{SIT, "705", 2, "Tolar", true},
+	{ZWL, "932", 2, "Zimbabwe Dollar", true},
+	{CNH, "", 2, "Yuan Renminbi (offshore)", false},
+	{CLF, "990", 4, "Unidad de Fomento", false},
+}
+
+var registry = make(map[Currency]*CurrencyInfo, len(currencyInfos))
+
+func init() {
+	for i := range currencyInfos {
+		registry[currencyInfos[i].Code] = &currencyInfos[i]
+	}
+}
+
+// Info returns the ISO 4217 data of the currency. It returns false if c is not
+// a known currency.
+func (c Currency) Info() (CurrencyInfo, bool) {
+	info, ok := registry[c]
+
+	if !ok {
+		return CurrencyInfo{Code: c, MinorUnits: -1}, false
+	}
+
+	return *info, true
+}
+
+// MinorUnits returns the number of decimal places of the minor unit of the
+// currency, or -1 if the currency is unknown or has no minor unit.
+func (c Currency) MinorUnits() int {
+	info, _ := c.Info()
+	return info.MinorUnits
+}
+
+// NumericCode returns the ISO 4217 numeric code of the currency, or an empty
+// string if none is assigned.
+func (c Currency) NumericCode() string {
+	info, _ := c.Info()
+	return info.Numeric
+}
+
+// Name returns the English name of the currency, or an empty string if the
+// currency is unknown.
+func (c Currency) Name() string {
+	info, _ := c.Info()
+	return info.Name
+}
+
+// IsActive reports whether the currency is known and has not been withdrawn.
+func (c Currency) IsActive() bool {
+	info, ok := c.Info()
+	return ok && !info.Withdrawn
+}
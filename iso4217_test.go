@@ -0,0 +1,51 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package currency
+
+import "testing"
+
+func TestCurrencyRegistry(t *testing.T) {
+	if len(registry) != len(currencies) {
+		t.Fatalf("expect %d registry entries, got %d", len(currencies), len(registry))
+	}
+
+	for _, cur := range currencies {
+		info, ok := cur.Info()
+
+		if !ok || info.Code != cur || info.Name == "" {
+			t.Fatalf("expect registry entry for %s, got %+v", cur, info)
+		}
+
+		if info.Numeric != "" && len(info.Numeric) != 3 {
+			t.Fatalf("%s: invalid numeric code %q", cur, info.Numeric)
+		}
+	}
+
+	tests := []struct {
+		cur     Currency
+		minor   int
+		numeric string
+		name    string
+		active  bool
+	}{
+		{USD, 2, "840", "US Dollar", true},
+		{JPY, 0, "392", "Yen", true},
+		{BHD, 3, "048", "Bahraini Dinar", true},
+		{CLF, 4, "990", "Unidad de Fomento", true},
+		{XAU, -1, "959", "Gold", true},
+		{XAG, -1, "961", "Silver", true},
+		{DEM, 2, "276", "Deutsche Mark", false},
+		{CNH, 2, "", "Yuan Renminbi (offshore)", true},
+		{Currency("ABC"), -1, "", "", false},
+	}
+
+	for i, test := range tests {
+		if test.cur.MinorUnits() != test.minor || test.cur.NumericCode() != test.numeric ||
+			test.cur.Name() != test.name || test.cur.IsActive() != test.active {
+			info, _ := test.cur.Info()
+			t.Fatalf("test %d: expect %d %q %q %v, got %+v", i, test.minor, test.numeric, test.name, test.active, info)
+		}
+	}
+}
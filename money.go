@@ -0,0 +1,208 @@
+package currency
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrCurrencyMismatch is returned when combining or comparing Money in
+// different currencies.
+var ErrCurrencyMismatch = errors.New("Currency mismatch")
+
+// ErrInvalidRatios is returned by Allocate and Split unless given at least
+// one positive ratio and no negative ones.
+var ErrInvalidRatios = errors.New("Ratios should be non-negative and not all zero")
+
+// ErrUnroundedAmount is returned by Allocate and Split for an amount with more
+// decimal places than the minor unit of its currency. Use Round first.
+var ErrUnroundedAmount = errors.New("Amount has more decimal places than the currency's minor unit")
+
+// allocationPlaces is the number of decimal places Allocate uses for
+// currencies without a minor unit, such as precious metals.
+const allocationPlaces = 4
+
+// Money is an amount in a currency. Arithmetic and comparison of Money fail
+// with ErrCurrencyMismatch unless both values are in the same currency; use a
+// Converter to convert between currencies.
+type Money struct {
+	Amount   decimal.Decimal `json:"amount"`
+	Currency Currency        `json:"currency"`
+}
+
+// NewMoney returns the given amount in the currency cur.
+func NewMoney(amount decimal.Decimal, cur Currency) Money {
+	return Money{Amount: amount, Currency: cur}
+}
+
+// ParseMoney returns the amount represented by the string, as parsed by
+// ParseAmount, in the currency cur.
+func ParseMoney(amount string, cur Currency) (Money, error) {
+	v, err := ParseAmount(amount)
+
+	if err != nil {
+		return Money{}, err
+	}
+
+	return NewMoney(v, cur), nil
+}
+
+// Add returns m + o.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return NewMoney(m.Amount.Add(o.Amount), m.Currency), nil
+}
+
+// Sub returns m - o.
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return NewMoney(m.Amount.Sub(o.Amount), m.Currency), nil
+}
+
+// Mul returns m multiplied by the factor d.
+func (m Money) Mul(d decimal.Decimal) Money {
+	return NewMoney(m.Amount.Mul(d), m.Currency)
+}
+
+// Neg returns -m.
+func (m Money) Neg() Money {
+	return NewMoney(m.Amount.Neg(), m.Currency)
+}
+
+// Abs returns the absolute value of m.
+func (m Money) Abs() Money {
+	return NewMoney(m.Amount.Abs(), m.Currency)
+}
+
+// Round returns m rounded to the minor units of its currency using the given
+// rounding mode.
+func (m Money) Round(mode RoundingMode) Money {
+	return NewMoney(mode.RoundCurrency(m.Amount, m.Currency), m.Currency)
+}
+
+// Allocate splits m into parts proportional to the given ratios, e.g. 1, 1, 2
+// for a quarter, a quarter and a half. The parts are multiples of the minor
+// unit of the currency and sum exactly to m: the minor units left over after
+// rounding the parts down are distributed one each to the parts with the
+// largest remainders, the first parts winning ties. For currencies without a
+// minor unit, and unknown currencies, the parts have 4 decimal places.
+//
+// ErrUnroundedAmount is returned if m has more decimal places than the parts,
+// e.g. the unrounded result of a conversion; round it with Round first.
+func (m Money) Allocate(ratios ...int) ([]Money, error) {
+	sum := 0
+
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, ErrInvalidRatios
+		}
+
+		sum += r
+	}
+
+	if sum == 0 {
+		return nil, ErrInvalidRatios
+	}
+
+	places := int32(m.Currency.MinorUnits())
+
+	if places < 0 {
+		places = allocationPlaces
+	}
+
+	// allocate the absolute amount in minor units.
+	units := m.Amount.Abs().Shift(places)
+
+	if !units.Equal(units.Truncate(0)) {
+		return nil, ErrUnroundedAmount
+	}
+
+	total := decimal.New(int64(sum), 0)
+	parts := make([]decimal.Decimal, len(ratios))
+	rems := make([]decimal.Decimal, len(ratios))
+	left := units
+
+	for i, r := range ratios {
+		parts[i], rems[i] = units.Mul(decimal.New(int64(r), 0)).QuoRem(total, 0)
+		left = left.Sub(parts[i])
+	}
+
+	order := make([]int, len(ratios))
+
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		return rems[order[a]].Cmp(rems[order[b]]) > 0
+	})
+
+	for i := 0; left.Sign() > 0; i++ {
+		parts[order[i]] = parts[order[i]].Add(oneD)
+		left = left.Sub(oneD)
+	}
+
+	res := make([]Money, len(ratios))
+
+	for i, p := range parts {
+		if m.Amount.Sign() < 0 {
+			p = p.Neg()
+		}
+
+		res[i] = NewMoney(p.Shift(-places), m.Currency)
+	}
+
+	return res, nil
+}
+
+// Split splits m into n parts as equal as possible which sum exactly to m.
+// See Allocate.
+func (m Money) Split(n int) ([]Money, error) {
+	if n < 1 {
+		return nil, ErrInvalidRatios
+	}
+
+	ratios := make([]int, n)
+
+	for i := range ratios {
+		ratios[i] = 1
+	}
+
+	return m.Allocate(ratios...)
+}
+
+// Cmp compares m and o and returns -1 if m < o, 0 if m == o and +1 if m > o.
+func (m Money) Cmp(o Money) (int, error) {
+	if m.Currency != o.Currency {
+		return 0, ErrCurrencyMismatch
+	}
+
+	return m.Amount.Cmp(o.Amount), nil
+}
+
+// Equal reports whether m and o are the same amount in the same currency.
+func (m Money) Equal(o Money) bool {
+	return m.Currency == o.Currency && m.Amount.Equal(o.Amount)
+}
+
+// Sign returns -1 if m < 0, 0 if m == 0 and +1 if m > 0.
+func (m Money) Sign() int {
+	return m.Amount.Sign()
+}
+
+// IsZero reports whether m is zero.
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+// String returns the amount followed by the currency, e.g. "12.5 USD".
+func (m Money) String() string {
+	return m.Amount.String() + " " + string(m.Currency)
+}
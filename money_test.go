@@ -0,0 +1,198 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package currency
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMoney(t *testing.T) {
+	a := NewMoney(decimal.RequireFromString("12.50"), USD)
+	b := NewMoney(decimal.RequireFromString("0.75"), USD)
+	e := NewMoney(decimal.RequireFromString("1"), EUR)
+
+	sum, err := a.Add(b)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sum.String() != "13.25 USD" {
+		t.Fatalf("expect 13.25 USD, got %s", sum)
+	}
+
+	diff, err := b.Sub(a)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff.String() != "-11.75 USD" || diff.Sign() != -1 || !diff.Neg().Equal(diff.Abs()) {
+		t.Fatalf("expect -11.75 USD, got %s", diff)
+	}
+
+	if m := a.Mul(decimal.New(2, 0)); m.String() != "25 USD" {
+		t.Fatalf("expect 25 USD, got %s", m)
+	}
+
+	if cmp, err := a.Cmp(b); err != nil || cmp != 1 {
+		t.Fatalf("expect 1, got %d, %v", cmp, err)
+	}
+
+	if _, err := a.Add(e); err != ErrCurrencyMismatch {
+		t.Fatalf("expect ErrCurrencyMismatch, got %v", err)
+	}
+
+	if _, err := a.Sub(e); err != ErrCurrencyMismatch {
+		t.Fatalf("expect ErrCurrencyMismatch, got %v", err)
+	}
+
+	if _, err := a.Cmp(e); err != ErrCurrencyMismatch {
+		t.Fatalf("expect ErrCurrencyMismatch, got %v", err)
+	}
+
+	if e.Equal(NewMoney(decimal.New(1, 0), USD)) {
+		t.Fatal("expect money in different currencies not to be equal")
+	}
+
+	buf, err := json.Marshal(a)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(buf) != `{"amount":"12.5","currency":"USD"}` {
+		t.Fatalf("unexpected json %s", buf)
+	}
+
+	if _, err := ParseMoney("12,5x", USD); err == nil {
+		t.Fatal("expect error")
+	}
+}
+
+func TestConvertMoney(t *testing.T) {
+	cc := NewWithProvider(newStubProvider())
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+	m, err := ParseMoney("100", EUR)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := cc.ConvertMoneyAt(m, USD, at)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Currency != USD || res.Amount.StringFixed(2) != "112.56" {
+		t.Fatalf("expect 112.56 USD, got %s", res)
+	}
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	tests := []struct {
+		value  string
+		cur    Currency
+		ratios []int
+		exp    []string
+	}{
+		{"100", USD, []int{1, 1, 1}, []string{"33.34", "33.33", "33.33"}},
+		{"0.05", USD, []int{3, 7}, []string{"0.02", "0.03"}},
+		{"0.05", USD, []int{7, 3}, []string{"0.04", "0.01"}},
+		{"-100", USD, []int{1, 1, 1}, []string{"-33.34", "-33.33", "-33.33"}},
+		{"10", JPY, []int{1, 2, 0, 3}, []string{"2", "3", "0", "5"}},
+		{"1", BHD, []int{1, 1, 1}, []string{"0.334", "0.333", "0.333"}},
+		{"1", XAU, []int{1, 2}, []string{"0.3333", "0.6667"}},
+		{"1", XAU, []int{1, 1, 1}, []string{"0.3334", "0.3333", "0.3333"}},
+		{"1", Currency("ABC"), []int{1, 1, 1}, []string{"0.3334", "0.3333", "0.3333"}},
+		{"0", EUR, []int{1, 1}, []string{"0", "0"}},
+		{"0.01", EUR, []int{1, 1, 1}, []string{"0.01", "0", "0"}},
+	}
+
+	for i, test := range tests {
+		m := NewMoney(decimal.RequireFromString(test.value), test.cur)
+		parts, err := m.Allocate(test.ratios...)
+
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+
+		if len(parts) != len(test.exp) {
+			t.Fatalf("test %d: expect %d parts, got %d", i, len(test.exp), len(parts))
+		}
+
+		sum := NewMoney(decimal.Zero, test.cur)
+
+		for j, p := range parts {
+			if p.Currency != test.cur || !p.Amount.Equal(decimal.RequireFromString(test.exp[j])) {
+				t.Fatalf("test %d: expect %v, got %v", i, test.exp, parts)
+			}
+
+			sum, _ = sum.Add(p)
+		}
+
+		if !sum.Equal(m) {
+			t.Fatalf("test %d: expect parts to sum to %s, got %s", i, m, sum)
+		}
+	}
+
+	m := NewMoney(decimal.New(1, 0), USD)
+
+	for _, ratios := range [][]int{nil, {0, 0}, {1, -1}} {
+		if _, err := m.Allocate(ratios...); err != ErrInvalidRatios {
+			t.Fatalf("expect ErrInvalidRatios for %v, got %v", ratios, err)
+		}
+	}
+
+	for _, v := range []string{"10.005", "33.333333333333333"} {
+		m := NewMoney(decimal.RequireFromString(v), USD)
+
+		if _, err := m.Allocate(1, 1); err != ErrUnroundedAmount {
+			t.Fatalf("expect ErrUnroundedAmount for %s, got %v", v, err)
+		}
+
+		if _, err := m.Round(RoundHalfEven).Allocate(1, 1); err != nil {
+			t.Fatalf("expect rounded %s to allocate, got %v", v, err)
+		}
+	}
+}
+
+func TestMoneySplit(t *testing.T) {
+	m := NewMoney(decimal.RequireFromString("1000.01"), EUR)
+	parts, err := m.Split(7)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := NewMoney(decimal.Zero, EUR)
+
+	for i, p := range parts {
+		exp := "142.86"
+
+		if i == 6 {
+			exp = "142.85"
+		}
+
+		if p.Amount.StringFixed(2) != exp {
+			t.Fatalf("part %d: expect %s, got %s", i, exp, p)
+		}
+
+		sum, _ = sum.Add(p)
+	}
+
+	if !sum.Equal(m) {
+		t.Fatalf("expect parts to sum to %s, got %s", m, sum)
+	}
+
+	if _, err := m.Split(0); err != ErrInvalidRatios {
+		t.Fatalf("expect ErrInvalidRatios, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package currency
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// An Option configures an Exchange or a Converter.
+type Option func(*options)
+
+type options struct {
+	httpClient   *http.Client
+	baseURL      string
+	clock        Clock
+	retry        RetryPolicy
+	unknownFunc  func(t time.Time, codes []Currency)
+	divPrecision int32
+	divRounding  RoundingMode
+	round        bool
+	rounding     RoundingMode
+	cacheSize    int
+	cacheTTL     time.Duration
+	todayTTL     time.Duration
+	store        RateStore
+	storeErrFunc func(t time.Time, err error)
+	fallback     Fallback
+	fallbackDays int
+	pubTime      time.Duration
+	pubLocation  *time.Location
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		clock:        systemClock{},
+		retry:        DefaultRetryPolicy,
+		divPrecision: -1,
+		divRounding:  RoundHalfUp,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithHTTPClient sets the http.Client used by the provider to make its
+// requests. It applies to a FixerProvider, including the one created by New,
+// and to an ECBProvider. The Exchange uses a copy of the provider, so the
+// caller's value is not modified. Other providers make their own requests and
+// are configured directly.
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = c
+	}
+}
+
+// WithBaseURL overrides the location of the API used by the provider, e.g. to
+// use HTTPS or a mirror of the API. Like WithHTTPClient it applies to a
+// FixerProvider and to an ECBProvider.
+func WithBaseURL(url string) Option {
+	return func(o *options) {
+		o.baseURL = url
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed rate fetches. The
+// default is DefaultRetryPolicy.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = p
+	}
+}
+
+// WithClock sets the clock used by the Exchange. It's intended for tests.
+func WithClock(c Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// WithUnknownCurrencyFunc sets a function which is called with the codes not
+// known to ParseCurrency whenever the provider returns such codes for a date.
+// The rates for these codes are still added to the cache and are available
+// through Exchange.Get. The function is called synchronously and must not use
+// the Exchange.
+func WithUnknownCurrencyFunc(f func(t time.Time, codes []Currency)) Option {
+	return func(o *options) {
+		o.unknownFunc = f
+	}
+}
+
+// WithDivisionPrecision sets the number of decimal places kept when an inverse
+// rate (ExchangeRate.ToEUR) or a cross rate between two currencies is derived.
+// The default is decimal.DivisionPrecision. A negative precision restores the
+// default.
+func WithDivisionPrecision(places int32) Option {
+	return func(o *options) {
+		o.divPrecision = places
+	}
+}
+
+// WithDivisionRounding sets the rounding mode used when an inverse or a cross
+// rate is derived. The default is RoundHalfUp.
+func WithDivisionRounding(mode RoundingMode) Option {
+	return func(o *options) {
+		o.divRounding = mode
+	}
+}
+
+// WithRounding rounds the results of conversions to the minor units of the
+// target currency, e.g. 2 decimal places for USD and none for JPY, using the
+// given rounding mode. Results in currencies without a minor unit, such as
+// precious metals, are not rounded. By default results are not rounded.
+func WithRounding(mode RoundingMode) Option {
+	return func(o *options) {
+		o.round = true
+		o.rounding = mode
+	}
+}
+
+// WithCacheSize limits the number of dates an Exchange keeps in its cache.
+// When the limit is exceeded the least recently used date is evicted. Zero,
+// the default, means no limit. Dates added by Exchange.Load are never evicted
+// and do not count towards the limit.
+func WithCacheSize(dates int) Option {
+	return func(o *options) {
+		o.cacheSize = dates
+	}
+}
+
+// WithCacheTTL sets how long the exchange rates of a date are kept in the
+// cache before they are fetched again. Zero, the default, means the rates
+// never expire. Dates added by Exchange.Load never expire.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.cacheTTL = ttl
+	}
+}
+
+// WithTodayTTL sets how long the exchange rates of the current date, or a
+// later date, are kept in the cache. It overrides WithCacheTTL for these dates,
+// whose rates may change once the day's fixing is published. Zero, the
+// default, applies the WithCacheTTL setting.
+func WithTodayTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.todayTTL = ttl
+	}
+}
+
+// WithStore sets a persistent store the Exchange consults before it fetches
+// rates from its provider. The rates fetched for dates before the current date
+// are added to the store. Errors returned by the store do not fail a lookup:
+// the rates are fetched from the provider instead, or the fetched rates used
+// although storing them failed, and the error is passed to the function set
+// by WithStoreErrorFunc.
+func WithStore(s RateStore) Option {
+	return func(o *options) {
+		o.store = s
+	}
+}
+
+// WithStoreErrorFunc sets a function called with the date and the error when
+// loading, locking or storing the rates of a date in the store set by
+// WithStore fails.
+func WithStoreErrorFunc(f func(t time.Time, err error)) Option {
+	return func(o *options) {
+		o.storeErrFunc = f
+	}
+}
+
+// WithFallback sets the policy for dates without published rates. maxDays
+// limits the number of calendar days searched for published rates. The default
+// is FallbackNone.
+func WithFallback(f Fallback, maxDays int) Option {
+	return func(o *options) {
+		o.fallback = f
+		o.fallbackDays = maxDays
+	}
+}
+
+// WithPublication sets the time of day, as the duration since midnight, and
+// the location at which the provider publishes the rates of a date. It
+// overrides the provider's Publisher implementation, see Publisher for how
+// the cut-off is applied.
+func WithPublication(at time.Duration, loc *time.Location) Option {
+	return func(o *options) {
+		o.pubTime = at
+		o.pubLocation = loc
+	}
+}
+
+// div returns a / b rounded according to the division options.
+func (o *options) div(a, b decimal.Decimal) decimal.Decimal {
+	places := o.divPrecision
+
+	if places < 0 {
+		places = int32(decimal.DivisionPrecision)
+	}
+
+	return o.divRounding.Div(a, b, places)
+}
@@ -0,0 +1,99 @@
+package currency
+
+import (
+	"time"
+
+	"github.com/simonz05/currency/calendar"
+)
+
+// Publisher is implemented by a RateProvider whose rates for a date are
+// published once, at a fixed time of day. An Exchange uses it to take the date
+// of a requested instant in the publication time zone and, as long as the
+// rates of the current date are not published yet, to use the rates of the
+// previous business day. The cut-off only applies relative to the current
+// time: a past instant maps to its date even if it is before that date's
+// publication time.
+type Publisher interface {
+	// Publication returns the time of day, as the duration since midnight,
+	// and the location at which the rates of a date are published.
+	Publication() (time.Duration, *time.Location)
+}
+
+// ecbPublication is the time the ECB publishes the reference rates, around
+// 16:00 CET.
+const ecbPublication = 16 * time.Hour
+
+// ecbLocation is the time zone of the ECB. It falls back to a fixed CET zone,
+// without daylight saving time, if the time zone database is not available.
+var ecbLocation = loadLocation("Europe/Berlin", time.FixedZone("CET", 60*60))
+
+func loadLocation(name string, fallback *time.Location) *time.Location {
+	loc, err := time.LoadLocation(name)
+
+	if err != nil {
+		return fallback
+	}
+
+	return loc
+}
+
+// Publication implements the Publisher interface.
+func (p *ECBProvider) Publication() (time.Duration, *time.Location) {
+	return ecbPublication, ecbLocation
+}
+
+// Publication implements the Publisher interface. fixer.io serves the ECB
+// reference rates.
+func (p *FixerProvider) Publication() (time.Duration, *time.Location) {
+	return ecbPublication, ecbLocation
+}
+
+// Publication implements the Publisher interface.
+func (p *HistoryProvider) Publication() (time.Duration, *time.Location) {
+	return ecbPublication, ecbLocation
+}
+
+// publication returns the publication time and location set with
+// WithPublication or by the provider.
+func (ex *Exchange) publication() (time.Duration, *time.Location, bool) {
+	if ex.opts.pubLocation != nil {
+		return ex.opts.pubTime, ex.opts.pubLocation, true
+	}
+
+	if p, ok := ex.provider.(Publisher); ok {
+		at, loc := p.Publication()
+		return at, loc, loc != nil
+	}
+
+	return 0, nil, false
+}
+
+// rateTime maps t to a time in the publication time zone whose date is the
+// date of the rates to use for t. Dates are taken in the publication time zone.
+// Instants after now are treated as now, and dates whose rates are not
+// published by now are replaced by the previous business day.
+func (ex *Exchange) rateTime(t time.Time) time.Time {
+	at, loc, ok := ex.publication()
+
+	if !ok {
+		return t
+	}
+
+	now := ex.opts.clock.Now()
+
+	if t.After(now) {
+		t = now
+	}
+
+	t = t.In(loc)
+
+	for {
+		y, m, d := t.Date()
+
+		if !time.Date(y, m, d, 0, 0, 0, 0, loc).Add(at).After(now) {
+			return t
+		}
+
+		t = calendar.PreviousBusinessDay(t)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package currency
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExchangePublication(t *testing.T) {
+	p, err := NewHistoryProvider(strings.NewReader(testECBCSV))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cet := time.FixedZone("CET", 60*60)
+	clock := &fakeClock{}
+	cc := NewWithProvider(p, WithClock(clock), WithPublication(16*time.Hour, cet))
+
+	tests := []struct {
+		now time.Time
+		at  time.Time
+		exp string
+	}{
+		// before the cut-off the previous fixing is used.
+		{time.Date(2016, 9, 6, 15, 0, 0, 0, cet), time.Time{}, "1.1158"},
+		{time.Date(2016, 9, 6, 16, 30, 0, 0, cet), time.Time{}, "1.1256"},
+		{time.Date(2016, 9, 5, 15, 0, 0, 0, cet), time.Time{}, "1.1163"},
+		// 23:30 UTC is the next day in CET.
+		{time.Date(2016, 9, 7, 0, 0, 0, 0, time.UTC), time.Date(2016, 9, 5, 23, 30, 0, 0, time.UTC), "1.1256"},
+		// rates of past dates are available at any time of day.
+		{time.Date(2016, 9, 7, 0, 0, 0, 0, time.UTC), time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC), "1.1256"},
+		// future instants use the rates in effect now.
+		{time.Date(2016, 9, 6, 15, 0, 0, 0, cet), time.Date(2016, 9, 8, 0, 0, 0, 0, cet), "1.1158"},
+	}
+
+	for i, test := range tests {
+		clock.now = test.now
+		res, err := cc.Convert(oneD, EUR, USD)
+
+		if !test.at.IsZero() {
+			res, err = cc.ConvertAt(oneD, EUR, USD, test.at)
+		}
+
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+
+		if res.String() != test.exp {
+			t.Fatalf("test %d: expect %s, got %s", i, test.exp, res)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package redisstore implements a currency.SharedStore on top of Redis. It
+// lets the replicas of a service share the exchange rates fetched by any of
+// them.
+//
+//	store := redisstore.New(redis.NewClient(&redis.Options{Addr: "localhost:6379"}))
+//	cc := currency.New("", currency.WithStore(store))
+package redisstore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/simonz05/currency"
+)
+
+// unlockScript deletes the lock only if it's still held by the caller.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// Store is a currency.SharedStore keeping the rates of each date as a JSON
+// value in Redis.
+type Store struct {
+	client redis.Cmdable
+
+	// Prefix is prepended to the keys. Defaults to "currency:".
+	Prefix string
+
+	// TTL is the expiry of the rates of past dates. Zero means the rates
+	// never expire.
+	TTL time.Duration
+
+	// RecentTTL is the expiry of the rates of the current date, which
+	// change once the day's fixing is published. Defaults to one hour.
+	RecentTTL time.Duration
+
+	// LockTTL bounds how long a lock is held if its holder dies before
+	// releasing it. Defaults to 30 seconds.
+	LockTTL time.Duration
+
+	// LockRetry is the interval at which a held lock is polled. Defaults to
+	// 100 milliseconds.
+	LockRetry time.Duration
+}
+
+// New initializes a Store using the given client.
+func New(client redis.Cmdable) *Store {
+	return &Store{
+		client:    client,
+		Prefix:    "currency:",
+		RecentTTL: time.Hour,
+		LockTTL:   30 * time.Second,
+		LockRetry: 100 * time.Millisecond,
+	}
+}
+
+func (s *Store) key(kind string, t time.Time) string {
+	return s.Prefix + kind + ":" + t.Format("2006-01-02")
+}
+
+// LoadRates implements the currency.RateStore interface.
+func (s *Store) LoadRates(ctx context.Context, t time.Time) (currency.Rates, error) {
+	buf, err := s.client.Get(ctx, s.key("rates", t)).Bytes()
+
+	if err == redis.Nil {
+		return nil, currency.ErrNoRates
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var rates currency.Rates
+
+	if err := json.Unmarshal(buf, &rates); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// StoreRates implements the currency.RateStore interface. The rates of the
+// current date expire after RecentTTL, those of past dates after TTL.
+func (s *Store) StoreRates(ctx context.Context, t time.Time, rates currency.Rates) error {
+	buf, err := json.Marshal(rates)
+
+	if err != nil {
+		return err
+	}
+
+	ttl := s.TTL
+	today := time.Now().In(t.Location()).Format("2006-01-02")
+
+	if t.Format("2006-01-02") >= today {
+		ttl = s.RecentTTL
+	}
+
+	return s.client.Set(ctx, s.key("rates", t), buf, ttl).Err()
+}
+
+// Lock implements the currency.SharedStore interface.
+func (s *Store) Lock(ctx context.Context, t time.Time) (func(), error) {
+	key := s.key("lock", t)
+	token, err := newToken()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		ok, err := s.client.SetNX(ctx, key, token, s.LockTTL).Result()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			break
+		}
+
+		select {
+		case <-time.After(s.LockRetry):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	unlock := func() {
+		// the lock expires after LockTTL if releasing it fails.
+		unlockScript.Run(context.Background(), s.client, []string{key}, token)
+	}
+
+	return unlock, nil
+}
+
+func newToken() (string, error) {
+	var b [16]byte
+
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b[:]), nil
+}
+
+// Name returns the name of the source used in currency.ExchangeRate.Source.
+func (s *Store) Name() string {
+	return "redis"
+}
@@ -0,0 +1,123 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redisstore
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/simonz05/currency"
+)
+
+type countingProvider struct {
+	calls int32
+}
+
+func (p *countingProvider) FetchRates(ctx context.Context, t time.Time) (currency.Rates, error) {
+	atomic.AddInt32(&p.calls, 1)
+	time.Sleep(50 * time.Millisecond)
+	return currency.Rates{currency.USD: decimal.RequireFromString("1.1256")}, nil
+}
+
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	s := New(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	s.LockRetry = 5 * time.Millisecond
+	return s, mr
+}
+
+func TestStore(t *testing.T) {
+	s, mr := newTestStore(t)
+	ctx := context.Background()
+	past := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+	today := time.Now()
+
+	if _, err := s.LoadRates(ctx, past); err != currency.ErrNoRates {
+		t.Fatalf("expect ErrNoRates, got %v", err)
+	}
+
+	rates := currency.Rates{currency.USD: decimal.RequireFromString("1.1256")}
+
+	for _, at := range []time.Time{past, today} {
+		if err := s.StoreRates(ctx, at, rates); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := s.LoadRates(ctx, past)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got[currency.USD].String() != "1.1256" {
+		t.Fatalf("expect 1.1256, got %s", got[currency.USD])
+	}
+
+	if ttl := mr.TTL(s.key("rates", past)); ttl != 0 {
+		t.Fatalf("expect past rates not to expire, got ttl %s", ttl)
+	}
+
+	if ttl := mr.TTL(s.key("rates", today)); ttl != time.Hour {
+		t.Fatalf("expect today's rates to expire after 1h, got ttl %s", ttl)
+	}
+}
+
+func TestStoreSharedFetch(t *testing.T) {
+	s, _ := newTestStore(t)
+	p := &countingProvider{}
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+
+	// every replica has its own Exchange but only one fetches the rates.
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ex := currency.NewExchange(p, currency.WithStore(s))
+
+			if _, err := ex.Get(at, currency.USD); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if calls := atomic.LoadInt32(&p.calls); calls != 1 {
+		t.Fatalf("expect 1 provider call, got %d", calls)
+	}
+}
+
+func TestStoreLockContext(t *testing.T) {
+	s, _ := newTestStore(t)
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+	unlock, err := s.Lock(context.Background(), at)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := s.Lock(ctx, at); err != context.DeadlineExceeded {
+		t.Fatalf("expect context.DeadlineExceeded, got %v", err)
+	}
+
+	unlock()
+
+	if unlock, err = s.Lock(context.Background(), at); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock()
+}
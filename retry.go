@@ -0,0 +1,208 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how an Exchange retries failed rate fetches. Only
+// retryable errors are retried: an HTTPError with a 429 or 5xx status, a
+// network timeout, a refused, reset or prematurely closed connection or an
+// error with a Retryable method returning true.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. The delay is doubled
+	// for every following retry.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between two attempts. Zero means no cap. If
+	// the server asks to wait longer through Retry-After the error is
+	// returned without retrying.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction, between 0 and 1, of the delay which is
+	// randomly subtracted to spread the retries of concurrent callers.
+	Jitter float64
+}
+
+// DefaultRetryPolicy is the RetryPolicy used unless WithRetryPolicy is given.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+	Jitter:      0.5,
+}
+
+// delay returns the delay before the next attempt after the given attempt
+// failed with err. A Retry-After given by the server takes precedence if it is
+// longer than the backoff. It returns false if the server asks to wait longer
+// than MaxDelay, in which case the error is returned instead of waiting. now
+// is used to turn a Retry-After date into a delay.
+func (p RetryPolicy) delay(attempt int, err error, now time.Time) (time.Duration, bool) {
+	d := p.BaseDelay
+
+	for i := 1; i < attempt && (p.MaxDelay == 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		d -= time.Duration(float64(d) * p.Jitter * rand.Float64())
+	}
+
+	var herr *HTTPError
+
+	if !errors.As(err, &herr) {
+		return d, true
+	}
+
+	wait := herr.RetryAfter
+
+	if !herr.RetryAt.IsZero() {
+		wait = herr.RetryAt.Sub(now)
+	}
+
+	if wait > d {
+		if p.MaxDelay > 0 && wait > p.MaxDelay {
+			return 0, false
+		}
+
+		d = wait
+	}
+
+	return d, true
+}
+
+// HTTPError is returned by the providers when a request fails with a non-2xx
+// status. URL is the request URL without its query, which may hold an API
+// token.
+type HTTPError struct {
+	URL        string
+	StatusCode int
+	Status     string
+
+	// RetryAfter is the delay requested by the server through the
+	// Retry-After header given in seconds, or zero.
+	RetryAfter time.Duration
+
+	// RetryAt is the time requested by the server through the Retry-After
+	// header given as a date, or zero.
+	RetryAt time.Time
+}
+
+func newHTTPError(r *http.Response) *HTTPError {
+	// the query is left out as it may hold an API token.
+	u := *r.Request.URL
+	u.RawQuery = ""
+	u.ForceQuery = false
+
+	err := &HTTPError{
+		URL:        u.Redacted(),
+		StatusCode: r.StatusCode,
+		Status:     r.Status,
+	}
+
+	if v := r.Header.Get("Retry-After"); v != "" {
+		if secs, perr := strconv.Atoi(v); perr == nil {
+			err.RetryAfter = time.Duration(secs) * time.Second
+		} else if t, perr := http.ParseTime(v); perr == nil {
+			err.RetryAt = t
+		}
+	}
+
+	return err
+}
+
+func (err *HTTPError) Error() string {
+	return fmt.Sprintf("%s: unexpected status %s", err.URL, err.Status)
+}
+
+// Retryable reports whether the request may succeed when retried.
+func (err *HTTPError) Retryable() bool {
+	return err.StatusCode == http.StatusTooManyRequests || err.StatusCode >= 500
+}
+
+func retryable(err error) bool {
+	var rerr interface{ Retryable() bool }
+
+	if errors.As(err, &rerr) {
+		return rerr.Retryable()
+	}
+
+	var nerr net.Error
+
+	if errors.As(err, &nerr) && nerr.Timeout() {
+		return true
+	}
+
+	// failing to connect and connections closed by the server or a proxy
+	// are usually transient.
+	var oerr *net.OpError
+
+	if errors.As(err, &oerr) && oerr.Op == "dial" {
+		return true
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	// net/http does not export the error returned when a server closes a
+	// kept-alive connection while the request is sent on it.
+	return strings.Contains(err.Error(), "http: server closed idle connection")
+}
+
+// fetch fetches the rates for t from the provider and retries according to
+// the retry policy. It also returns the date the rates were published for.
+func (ex *Exchange) fetch(ctx context.Context, t time.Time) (Rates, time.Time, error) {
+	if ex.provider == nil {
+		return nil, time.Time{}, ErrFetchingData
+	}
+
+	policy := ex.opts.retry
+
+	for attempt := 1; ; attempt++ {
+		rates, published, err := ex.fetchOnce(ctx, t)
+
+		if err == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil || !retryable(err) {
+			return rates, published, err
+		}
+
+		d, ok := policy.delay(attempt, err, ex.opts.clock.Now())
+
+		if !ok {
+			return rates, published, err
+		}
+
+		select {
+		case <-ex.opts.clock.After(d):
+		case <-ctx.Done():
+			return nil, time.Time{}, ctx.Err()
+		}
+	}
+}
+
+func (ex *Exchange) fetchOnce(ctx context.Context, t time.Time) (Rates, time.Time, error) {
+	if p, ok := ex.provider.(DatedProvider); ok {
+		return p.FetchDatedRates(ctx, t)
+	}
+
+	rates, err := ex.provider.FetchRates(ctx, t)
+	return rates, t, err
+}
@@ -0,0 +1,236 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package currency
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock records the requested delays and fires timers immediately.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	delays []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.delays = append(c.delays, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestExchangeRetry(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/2016-09-06") {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+
+		attempts++
+
+		switch attempts {
+		case 1:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Write([]byte(testFixerJSON))
+		}
+	}))
+	defer srv.Close()
+
+	clock := &fakeClock{now: time.Date(2016, 9, 7, 12, 0, 0, 0, time.UTC)}
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	cc := New("token", WithBaseURL(srv.URL), WithRetryPolicy(policy), WithClock(clock))
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+
+	if _, err := cc.ConvertStringAt("1", USD, EUR, at); err != nil {
+		t.Fatal(err)
+	}
+
+	if attempts != 3 {
+		t.Fatalf("expect 3 attempts, got %d", attempts)
+	}
+
+	if len(clock.delays) != 2 || clock.delays[0] != 7*time.Second || clock.delays[1] != 2*time.Second {
+		t.Fatalf("expect delays [7s 2s], got %v", clock.delays)
+	}
+}
+
+func TestExchangeRetryNotRetryable(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	cc := New("secret", WithBaseURL(srv.URL), WithClock(&fakeClock{now: time.Date(2016, 9, 7, 12, 0, 0, 0, time.UTC)}))
+	_, err := cc.ConvertStringAt("1", USD, EUR, time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC))
+	herr, ok := err.(*HTTPError)
+
+	if !ok || herr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expect HTTPError 404, got %v", err)
+	}
+
+	if strings.Contains(herr.Error(), "secret") {
+		t.Fatalf("expect API token to be redacted, got %s", herr)
+	}
+
+	if attempts != 1 {
+		t.Fatalf("expect 1 attempt, got %d", attempts)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second, Jitter: 0.5}
+
+	for attempt, max := range []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if attempt == 0 {
+			continue
+		}
+
+		for i := 0; i < 10; i++ {
+			d, ok := p.delay(attempt, nil, time.Time{})
+
+			if !ok || d > max || d < max/2 {
+				t.Fatalf("attempt %d: expect delay in [%s, %s], got %s", attempt, max/2, max, d)
+			}
+		}
+	}
+}
+
+func TestExchangeRetryAfterExceedsMaxDelay(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "86400")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	clock := &fakeClock{now: time.Date(2016, 9, 7, 12, 0, 0, 0, time.UTC)}
+	cc := New("token", WithBaseURL(srv.URL), WithClock(clock))
+	_, err := cc.ConvertStringAt("1", USD, EUR, time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC))
+
+	if herr, ok := err.(*HTTPError); !ok || herr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expect HTTPError 503, got %v", err)
+	}
+
+	if attempts != 1 || len(clock.delays) != 0 {
+		t.Fatalf("expect 1 attempt without delay, got %d attempts, delays %v", attempts, clock.delays)
+	}
+}
+
+func TestRetryableConnectionErrors(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := l.Addr().String()
+	l.Close()
+
+	_, err = http.Get("http://" + addr)
+
+	if err == nil || !retryable(err) {
+		t.Fatalf("expect connection refused to be retryable, got %v", err)
+	}
+
+	// a server closing the connection after reading the request, without a
+	// response.
+	l, err = net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer l.Close()
+
+	go func() {
+		for {
+			c, err := l.Accept()
+
+			if err != nil {
+				return
+			}
+
+			http.ReadRequest(bufio.NewReader(c))
+			c.Close()
+		}
+	}()
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	_, err = client.Get("http://" + l.Addr().String())
+
+	if err == nil || !retryable(err) {
+		t.Fatalf("expect closed connection to be retryable, got %v", err)
+	}
+
+	idle := &url.Error{Op: "Get", URL: "http://example.com", Err: errors.New("http: server closed idle connection")}
+
+	if !retryable(idle) {
+		t.Fatal("expect closed idle connection to be retryable")
+	}
+
+	if retryable(&HTTPError{StatusCode: http.StatusNotFound}) {
+		t.Fatal("expect 404 not to be retryable")
+	}
+}
+
+func TestExchangeRetryAfterDate(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2016, 9, 7, 12, 0, 0, 0, time.UTC)}
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		if attempts == 1 {
+			w.Header().Set("Retry-After", clock.Now().Add(4*time.Second).Format(http.TimeFormat))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		w.Write([]byte(testFixerJSON))
+	}))
+	defer srv.Close()
+
+	policy := RetryPolicy{MaxAttempts: 2, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	cc := New("token", WithBaseURL(srv.URL), WithRetryPolicy(policy), WithClock(clock))
+
+	if _, err := cc.ConvertStringAt("1", USD, EUR, time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(clock.delays) != 1 || clock.delays[0] != 4*time.Second {
+		t.Fatalf("expect delays [4s], got %v", clock.delays)
+	}
+}
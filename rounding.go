@@ -0,0 +1,94 @@
+package currency
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+var twoD = decimal.New(2, 0)
+
+// RoundingMode specifies how a value is rounded to a number of decimal places.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest value, halves away from zero.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest value, halves to the even
+	// neighbour. Also known as banker's rounding.
+	RoundHalfEven
+	// RoundDown rounds towards zero.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+)
+
+// String returns the name of the rounding mode.
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundHalfUp:
+		return "half-up"
+	case RoundHalfEven:
+		return "half-even"
+	case RoundDown:
+		return "down"
+	case RoundUp:
+		return "up"
+	default:
+		return "unknown"
+	}
+}
+
+// Round rounds d to the given number of decimal places.
+func (m RoundingMode) Round(d decimal.Decimal, places int32) decimal.Decimal {
+	switch m {
+	case RoundHalfEven:
+		return d.RoundBank(places)
+	case RoundDown:
+		return d.RoundDown(places)
+	case RoundUp:
+		return d.RoundUp(places)
+	default:
+		return d.Round(places)
+	}
+}
+
+// RoundCurrency rounds d to the minor units of the currency cur, see
+// Currency.MinorUnits. d is returned unchanged if cur has no minor unit.
+func (m RoundingMode) RoundCurrency(d decimal.Decimal, cur Currency) decimal.Decimal {
+	places := cur.MinorUnits()
+
+	if places < 0 {
+		return d
+	}
+
+	return m.Round(d, int32(places))
+}
+
+// Div returns a / b rounded to the given number of decimal places. Unlike
+// rounding the result of decimal.Div the quotient is rounded exactly once.
+func (m RoundingMode) Div(a, b decimal.Decimal, places int32) decimal.Decimal {
+	q, r := a.QuoRem(b, places)
+
+	if r.IsZero() || m == RoundDown {
+		return q
+	}
+
+	ulp := decimal.New(1, -places)
+
+	if a.Sign()*b.Sign() < 0 {
+		ulp = ulp.Neg()
+	}
+
+	away := m == RoundUp
+
+	if m == RoundHalfUp || m == RoundHalfEven {
+		// compare the remainder, in units of the last place, with one half.
+		c := r.Abs().Mul(twoD).Cmp(b.Abs().Mul(decimal.New(1, -places)))
+		away = c > 0 || c == 0 && (m == RoundHalfUp || q.Shift(places).Mod(twoD).Sign() != 0)
+	}
+
+	if away {
+		return q.Add(ulp)
+	}
+
+	return q
+}
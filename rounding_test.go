@@ -0,0 +1,105 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package currency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestRoundingModeDiv(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		places int32
+		mode   RoundingMode
+		exp    string
+	}{
+		{"2", "3", 3, RoundHalfUp, "0.667"},
+		{"2", "3", 3, RoundDown, "0.666"},
+		{"1", "3", 3, RoundUp, "0.334"},
+		{"-2", "3", 3, RoundHalfUp, "-0.667"},
+		{"-1", "3", 3, RoundUp, "-0.334"},
+		{"1", "8", 2, RoundHalfUp, "0.13"},
+		{"1", "8", 2, RoundHalfEven, "0.12"},
+		{"3", "8", 2, RoundHalfEven, "0.38"},
+		{"-1", "8", 2, RoundHalfEven, "-0.12"},
+		{"1", "4", 2, RoundUp, "0.25"},
+		{"1", "1.1256", 4, RoundHalfUp, "0.8884"},
+	}
+
+	for i, test := range tests {
+		a := decimal.RequireFromString(test.a)
+		b := decimal.RequireFromString(test.b)
+		res := test.mode.Div(a, b, test.places)
+
+		if res.String() != test.exp {
+			t.Fatalf("test %d: %s / %s (%s): expect %s, got %s", i, test.a, test.b, test.mode, test.exp, res)
+		}
+	}
+}
+
+func TestRoundingModeRoundCurrency(t *testing.T) {
+	tests := []struct {
+		value string
+		cur   Currency
+		mode  RoundingMode
+		exp   string
+	}{
+		{"1234.5", JPY, RoundHalfUp, "1235"},
+		{"1234.5", JPY, RoundHalfEven, "1234"},
+		{"1.2345", BHD, RoundHalfEven, "1.234"},
+		{"1.2345", BHD, RoundHalfUp, "1.235"},
+		{"1.001", USD, RoundUp, "1.01"},
+		{"1.009", USD, RoundDown, "1"},
+		{"-1.005", USD, RoundHalfUp, "-1.01"},
+		{"1.23456", XAU, RoundHalfUp, "1.23456"},
+	}
+
+	for i, test := range tests {
+		res := test.mode.RoundCurrency(decimal.RequireFromString(test.value), test.cur)
+
+		if res.String() != test.exp {
+			t.Fatalf("test %d: expect %s, got %s", i, test.exp, res)
+		}
+
+		m := NewMoney(decimal.RequireFromString(test.value), test.cur).Round(test.mode)
+
+		if m.Amount.String() != test.exp {
+			t.Fatalf("test %d: expect %s, got %s", i, test.exp, m.Amount)
+		}
+	}
+}
+
+func TestConverterRounding(t *testing.T) {
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		from  Currency
+		to    Currency
+		mode  RoundingMode
+		exp   string
+	}{
+		{"1", EUR, USD, RoundHalfUp, "1.13"},
+		{"1", EUR, USD, RoundDown, "1.12"},
+		{"100", PLN, USD, RoundHalfEven, "25.98"},
+		{"0.5", EUR, USD, RoundUp, "0.57"},
+	}
+
+	for i, test := range tests {
+		cc := NewWithProvider(newStubProvider(), WithRounding(test.mode))
+		res, err := cc.ConvertStringAt(test.value, test.from, test.to, at)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.String() != test.exp {
+			t.Fatalf("test %d: expect %s, got %s", i, test.exp, res)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package currency
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SQLStore is a RateStore keeping the rates in a database/sql table with one
+// row per date and currency. The table is created by CreateTable.
+type SQLStore struct {
+	db *sql.DB
+
+	// Table is the name of the table. Defaults to currency_rates.
+	Table string
+
+	// Numbered selects numbered placeholders ($1, $2, ...) as used by
+	// PostgreSQL instead of ?.
+	Numbered bool
+}
+
+// NewSQLStore initializes a SQLStore using db.
+func NewSQLStore(db *sql.DB) *SQLStore {
+	return &SQLStore{db: db, Table: "currency_rates"}
+}
+
+// query returns q with the table name substituted for {table} and the
+// placeholders rewritten if Numbered is set.
+func (s *SQLStore) query(q string) string {
+	table := s.Table
+
+	if table == "" {
+		table = "currency_rates"
+	}
+
+	q = strings.Replace(q, "{table}", table, -1)
+
+	if !s.Numbered {
+		return q
+	}
+
+	var b strings.Builder
+
+	for i, n := 0, 1; i < len(q); i++ {
+		if q[i] == '?' {
+			fmt.Fprintf(&b, "$%d", n)
+			n++
+			continue
+		}
+
+		b.WriteByte(q[i])
+	}
+
+	return b.String()
+}
+
+// CreateTable creates the table if it does not exist.
+func (s *SQLStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, s.query(`CREATE TABLE IF NOT EXISTS {table} (
+	date CHAR(10) NOT NULL,
+	currency CHAR(3) NOT NULL,
+	rate VARCHAR(64) NOT NULL,
+	PRIMARY KEY (date, currency)
+)`))
+	return err
+}
+
+// LoadRates implements the RateStore interface.
+func (s *SQLStore) LoadRates(ctx context.Context, t time.Time) (Rates, error) {
+	rows, err := s.db.QueryContext(ctx, s.query("SELECT currency, rate FROM {table} WHERE date = ?"), t.Format("2006-01-02"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	rates := make(Rates)
+
+	for rows.Next() {
+		var (
+			cur  string
+			rate string
+		)
+
+		if err := rows.Scan(&cur, &rate); err != nil {
+			return nil, err
+		}
+
+		v, err := decimal.NewFromString(rate)
+
+		if err != nil {
+			return nil, fmt.Errorf("sqlstore: invalid rate %q for %s", rate, cur)
+		}
+
+		rates[Currency(cur)] = v
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(rates) == 0 {
+		return nil, ErrNoRates
+	}
+
+	return rates, nil
+}
+
+// StoreRates implements the RateStore interface.
+func (s *SQLStore) StoreRates(ctx context.Context, t time.Time, rates Rates) error {
+	day := t.Format("2006-01-02")
+	tx, err := s.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, s.query("DELETE FROM {table} WHERE date = ?"), day); err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, s.query("INSERT INTO {table} (date, currency, rate) VALUES (?, ?, ?)"))
+
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	for cur, rate := range rates {
+		if _, err := stmt.ExecContext(ctx, day, string(cur), rate.String()); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Name returns the name of the source used in ExchangeRate.Source.
+func (s *SQLStore) Name() string {
+	return "sql"
+}
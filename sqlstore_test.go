@@ -0,0 +1,43 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// The SQLite driver requires cgo.
+
+//go:build cgo
+
+package currency
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestSQLStore(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "rates.db"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	for _, numbered := range []bool{false, true} {
+		s := NewSQLStore(db)
+		s.Numbered = numbered
+
+		if numbered {
+			s.Table = "currency_rates_numbered"
+		}
+
+		if err := s.CreateTable(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		testRateStore(t, s)
+	}
+}
@@ -0,0 +1,118 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RateStore is the interface implemented by a persistent store of exchange
+// rates. An Exchange configured with WithStore consults the store before it
+// fetches rates from its provider and stores the fetched rates of past dates,
+// so they are fetched only once across process restarts.
+//
+// It's safe to call the methods of a RateStore concurrently from multiple go
+// routines.
+type RateStore interface {
+	// LoadRates returns the rates stored for the date of t. It returns
+	// ErrNoRates if the store holds no rates for the date.
+	LoadRates(ctx context.Context, t time.Time) (Rates, error)
+
+	// StoreRates stores the rates for the date of t, replacing any rates
+	// stored for the date before.
+	StoreRates(ctx context.Context, t time.Time, rates Rates) error
+}
+
+// SharedStore is implemented by a RateStore shared between processes, such as
+// a cache server. An Exchange stores the rates of all dates in a SharedStore,
+// including the rates of the current date which the store should expire, and
+// holds the lock of a date while it fetches the rates, so other processes wait
+// for the rates to be stored instead of fetching them too.
+type SharedStore interface {
+	RateStore
+
+	// Lock acquires the lock for the date of t, waiting while another
+	// process holds it. The returned function releases the lock.
+	Lock(ctx context.Context, t time.Time) (unlock func(), err error)
+}
+
+// FileStore is a RateStore keeping the rates of each date in a JSON file in a
+// directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore initializes a FileStore using the given directory. The
+// directory is created when the first rates are stored.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+func (s *FileStore) name(t time.Time) string {
+	return filepath.Join(s.dir, string(toDate(t))+".json")
+}
+
+// LoadRates implements the RateStore interface.
+func (s *FileStore) LoadRates(ctx context.Context, t time.Time) (Rates, error) {
+	buf, err := ioutil.ReadFile(s.name(t))
+
+	if os.IsNotExist(err) {
+		return nil, ErrNoRates
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var rates Rates
+
+	if err := json.Unmarshal(buf, &rates); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// StoreRates implements the RateStore interface. The file is replaced
+// atomically so concurrent readers never see a partial file.
+func (s *FileStore) StoreRates(ctx context.Context, t time.Time, rates Rates) error {
+	buf, err := json.Marshal(rates)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(s.dir, ".rates-")
+
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(buf)
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err == nil {
+		err = os.Rename(f.Name(), s.name(t))
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+	}
+
+	return err
+}
+
+// Name returns the name of the source used in ExchangeRate.Source.
+func (s *FileStore) Name() string {
+	return "file"
+}
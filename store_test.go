@@ -0,0 +1,137 @@
+// Copyright 2018 Simon Zimmermann. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func testRateStore(t *testing.T, s RateStore) {
+	ctx := context.Background()
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+
+	if _, err := s.LoadRates(ctx, at); err != ErrNoRates {
+		t.Fatalf("expect ErrNoRates, got %v", err)
+	}
+
+	for _, usd := range []string{"1.1", "1.1256"} {
+		rates := Rates{
+			USD: decimal.RequireFromString(usd),
+			IDR: decimal.RequireFromString("14756.123456789012345678"),
+		}
+
+		if err := s.StoreRates(ctx, at, rates); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rates, err := s.LoadRates(ctx, at)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rates) != 2 || rates[USD].String() != "1.1256" || rates[IDR].String() != "14756.123456789012345678" {
+		t.Fatalf("unexpected rates %v", rates)
+	}
+
+	// the stored rates are used instead of fetching them again.
+	p := newStubProvider()
+	ex := NewExchange(p, WithStore(s))
+
+	if _, err := ex.Get(at, IDR); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.calls != 0 {
+		t.Fatalf("expect no provider calls, got %d", p.calls)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	testRateStore(t, NewFileStore(filepath.Join(t.TempDir(), "rates")))
+}
+
+func TestExchangeStoresFetchedRates(t *testing.T) {
+	s := NewFileStore(t.TempDir())
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+
+	if _, err := NewExchange(newStubProvider(), WithStore(s)).Get(at, USD); err != nil {
+		t.Fatal(err)
+	}
+
+	rate, err := NewExchange(nil, WithStore(s)).Get(at, PLN)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rate.FromEUR.String() != "4.3327" {
+		t.Fatalf("expect 4.3327, got %s", rate.FromEUR)
+	}
+
+	// rates for the current date are not stored as they may still change.
+	today := time.Now()
+	p := newDatesStubProvider(today)
+
+	if _, err := NewExchange(p, WithStore(s)).Get(today, USD); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.LoadRates(context.Background(), today); err != ErrNoRates {
+		t.Fatalf("expect ErrNoRates, got %v", err)
+	}
+}
+
+type failingStore struct {
+	loadErr error
+}
+
+func (s failingStore) LoadRates(ctx context.Context, t time.Time) (Rates, error) {
+	return nil, s.loadErr
+}
+
+func (s failingStore) StoreRates(ctx context.Context, t time.Time, rates Rates) error {
+	return errors.New("disk full")
+}
+
+func TestExchangeStoreErrors(t *testing.T) {
+	at := time.Date(2016, 9, 6, 0, 1, 0, 0, time.UTC)
+
+	tests := []struct {
+		loadErr error
+		reports int
+	}{
+		{fmt.Errorf("wrapped: %w", ErrNoRates), 1},
+		{errors.New("connection refused"), 2},
+	}
+
+	for i, test := range tests {
+		var reported []error
+		f := func(t time.Time, err error) { reported = append(reported, err) }
+		p := newStubProvider()
+		ex := NewExchange(p, WithStore(failingStore{test.loadErr}), WithStoreErrorFunc(f))
+		rate, err := ex.Get(at, USD)
+
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+
+		if rate.FromEUR.String() != "1.1256" || p.calls != 1 {
+			t.Fatalf("test %d: expect fetched rate 1.1256, got %s after %d calls", i, rate.FromEUR, p.calls)
+		}
+
+		if len(reported) != test.reports || reported[len(reported)-1].Error() != "disk full" {
+			t.Fatalf("test %d: expect %d store errors, got %v", i, test.reports, reported)
+		}
+	}
+}